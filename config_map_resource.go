@@ -0,0 +1,185 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/validation"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+)
+
+func configMapResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "default",
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"data": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"annotations": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+		},
+		Create: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace := r.Get("namespace").(string)
+			name := r.Get("name").(string)
+
+			item := &api.ConfigMap{}
+			item.Name = name
+
+			writeLabels(r, &item.ObjectMeta)
+			writeAnnotations(r, &item.ObjectMeta)
+			writeConfigMapData(r, item)
+
+			err := writeLastApplied(client.RESTClient, item)
+			if err != nil {
+				return err
+			}
+
+			item, err = client.ConfigMaps(namespace).Create(item)
+			if err != nil {
+				return err
+			}
+
+			r.SetId(join(namespace, name))
+			return nil
+		},
+		Read: resourceConfigMapRead,
+		Update: func(r *schema.ResourceData, v interface{}) error {
+
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			err = patchObject(client.RESTClient, "configmaps", r, v, func(r *schema.ResourceData) (runtime.Object, error) {
+				item := &api.ConfigMap{}
+				item.Namespace = namespace
+				item.Name = name
+
+				writeLabels(r, &item.ObjectMeta)
+				writeAnnotations(r, &item.ObjectMeta)
+				writeConfigMapData(r, item)
+				return item, nil
+			}, &api.ConfigMap{})
+			if err != nil {
+				return err
+			}
+
+			return resourceConfigMapRead(r, v)
+		},
+		Delete: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			return client.ConfigMaps(namespace).Delete(name)
+		},
+		Exists: func(r *schema.ResourceData, v interface{}) (bool, error) {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return false, err
+			}
+
+			_, err = client.ConfigMaps(namespace).Get(name)
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			return true, nil
+		},
+	}
+}
+
+func resourceConfigMapRead(r *schema.ResourceData, v interface{}) error {
+	client := extractClient(v)
+	namespace, name, err := split(r.Id())
+	if err != nil {
+		return err
+	}
+
+	item, err := client.ConfigMaps(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		r.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	readLabels(r, &item.ObjectMeta)
+	readAnnotations(r, &item.ObjectMeta)
+	readConfigMapData(r, item)
+
+	r.Set("name", item.ObjectMeta.Name)
+	return nil
+}
+
+func readConfigMapData(r *schema.ResourceData, c *api.ConfigMap) {
+	m := make(map[string]interface{})
+	if len(c.Data) > 0 {
+		for k, v := range c.Data {
+			m[k] = v
+		}
+	}
+	r.Set("data", m)
+}
+
+func writeConfigMapData(r *schema.ResourceData, c *api.ConfigMap) {
+	c.Data = map[string]string{}
+	if data, _ := r.Get("data").(map[string]interface{}); data != nil {
+		for k, v := range data {
+			if s, ok := v.(string); ok {
+				c.Data[k] = s
+			}
+		}
+	}
+}
+
+func validateConfigMapConfig(r *schema.ResourceData) field.ErrorList {
+	item := &api.ConfigMap{}
+	item.Namespace = r.Get("namespace").(string)
+	item.Name = r.Get("name").(string)
+
+	writeLabels(r, &item.ObjectMeta)
+	writeAnnotations(r, &item.ObjectMeta)
+	writeConfigMapData(r, item)
+
+	return validation.ValidateConfigMap(item)
+}
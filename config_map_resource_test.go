@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+)
+
+// TestConfigMapUpdate updates a config map that got another key since the
+// last refresh. The state holds the config map as it is after the patch.
+func TestConfigMapUpdate(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+
+	res := providerResource(configMapResource())
+	config := func(x string) map[string]interface{} {
+		return map[string]interface{}{
+			"name": "web",
+			"data": map[string]interface{}{"a": x},
+		}
+	}
+
+	state, err := apply(t, res, nil, config("1"), s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	const p = "/api/v1/namespaces/default/configmaps/web"
+	item := &api.ConfigMap{}
+	s.get(p, item)
+	item.Data["b"] = "2"
+	s.put(p, item)
+
+	state, err = apply(t, res, state, config("3"), s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]string{"data.#": "2", "data.a": "3", "data.b": "2"}
+	for k, x := range expected {
+		if state.Attributes[k] != x {
+			t.Fatalf("bad %s: %q", k, state.Attributes[k])
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+	"k8s.io/kubernetes/pkg/util/wait"
+)
+
+func daemonSetResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "default",
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"annotations": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"template": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem:     podTemplateResourceSpec,
+			},
+			"wait_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "2m",
+				ValidateFunc: validateDuration,
+			},
+			"delete_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "2m",
+				ValidateFunc: validateDuration,
+			},
+
+			"desired_number_scheduled": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"current_number_scheduled": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"number_misscheduled": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+
+		Read:   resourceDaemonSetRead,
+		Create: resourceDaemonSetCreate,
+		Update: resourceDaemonSetUpdate,
+		Delete: resourceDaemonSetDelete,
+		Exists: resourceDaemonSetExists,
+	}
+}
+
+func resourceDaemonSetRead(r *schema.ResourceData, v interface{}) error {
+	client := extractClient(v)
+	namespace, name, err := split(r.Id())
+	if err != nil {
+		return err
+	}
+
+	item, err := client.Extensions().DaemonSets(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		r.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	readLabels(r, &item.ObjectMeta)
+	readAnnotations(r, &item.ObjectMeta)
+
+	r.Set("desired_number_scheduled", item.Status.DesiredNumberScheduled)
+	r.Set("current_number_scheduled", item.Status.CurrentNumberScheduled)
+	r.Set("number_misscheduled", item.Status.NumberMisscheduled)
+
+	root := NewObjectBuilder(r, "")
+	t := root.NewList("template")
+	readPodTemplateSpec(t, &item.Spec.Template)
+	t.Apply()
+	return root.Apply()
+}
+
+func resourceDaemonSetCreate(r *schema.ResourceData, v interface{}) error {
+	client := extractClient(v)
+	namespace := r.Get("namespace").(string)
+	name := r.Get("name").(string)
+
+	item := &extensions.DaemonSet{}
+	item.Name = name
+
+	err := writeDaemonSet(r, item)
+	if err != nil {
+		return err
+	}
+
+	err = writeLastApplied(client.ExtensionsClient.RESTClient, item)
+	if err != nil {
+		return err
+	}
+
+	item, err = client.Extensions().DaemonSets(namespace).Create(item)
+	if err != nil {
+		return err
+	}
+
+	r.SetId(join(namespace, name))
+
+	timeout, err := readDuration(r, "wait_timeout", 2*time.Minute)
+	if err != nil {
+		return err
+	}
+
+	err = wait.Poll(1*time.Second, timeout, daemonSetScheduled(client, namespace, name))
+	if err != nil {
+		return err
+	}
+
+	return resourceDaemonSetRead(r, v)
+}
+
+func resourceDaemonSetUpdate(r *schema.ResourceData, v interface{}) error {
+	client := extractClient(v)
+	namespace, name, err := split(r.Id())
+	if err != nil {
+		return err
+	}
+
+	err = patchObject(client.ExtensionsClient.RESTClient, "daemonsets", r, v, func(r *schema.ResourceData) (runtime.Object, error) {
+		item := &extensions.DaemonSet{}
+		item.Namespace = namespace
+		item.Name = name
+
+		err := writeDaemonSet(r, item)
+		return item, err
+	}, &extensions.DaemonSet{})
+	if err != nil {
+		return err
+	}
+
+	timeout, err := readDuration(r, "wait_timeout", 2*time.Minute)
+	if err != nil {
+		return err
+	}
+
+	err = wait.Poll(1*time.Second, timeout, daemonSetScheduled(client, namespace, name))
+	if err != nil {
+		return err
+	}
+
+	return resourceDaemonSetRead(r, v)
+}
+
+func resourceDaemonSetDelete(r *schema.ResourceData, v interface{}) error {
+	client := extractClient(v)
+	namespace, name, err := split(r.Id())
+	if err != nil {
+		return err
+	}
+	daemonSets := client.Extensions().DaemonSets(namespace)
+
+	timeout, err := readDuration(r, "delete_timeout", 2*time.Minute)
+	if err != nil {
+		return err
+	}
+
+	// Deleting a daemon set doesn't cascade, so first make it match no node
+	// and wait for its pods to go away.
+	err = retryUpdate(func() error {
+		item, err := daemonSets.Get(name)
+		if err != nil {
+			return err
+		}
+
+		if item.Spec.Template.Spec.NodeSelector == nil {
+			item.Spec.Template.Spec.NodeSelector = map[string]string{}
+		}
+		item.Spec.Template.Spec.NodeSelector["terraform.io/deleting"] = string(item.UID)
+		_, err = daemonSets.Update(item)
+		return err
+	})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = wait.Poll(1*time.Second, timeout, func() (bool, error) {
+		item, err := daemonSets.Get(name)
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return item.Status.CurrentNumberScheduled+item.Status.NumberMisscheduled == 0, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = daemonSets.Delete(name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func resourceDaemonSetExists(r *schema.ResourceData, v interface{}) (bool, error) {
+	client := extractClient(v)
+	namespace, name, err := split(r.Id())
+	if err != nil {
+		return false, err
+	}
+
+	_, err = client.Extensions().DaemonSets(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func writeDaemonSet(r *schema.ResourceData, item *extensions.DaemonSet) error {
+	writeLabels(r, &item.ObjectMeta)
+	writeAnnotations(r, &item.ObjectMeta)
+
+	err := writePodTemplateSpec(r, &item.Spec.Template)
+	if err != nil {
+		return err
+	}
+
+	if len(item.Spec.Template.ObjectMeta.Labels) == 0 {
+		return fmt.Errorf("template labels must not be empty")
+	}
+	item.Spec.Selector = &unversioned.LabelSelector{
+		MatchLabels: item.Spec.Template.ObjectMeta.Labels,
+	}
+
+	return nil
+}
+
+// daemonSetScheduled is true once the daemon pod is scheduled on every node
+// it should run on. The status is all zeros until the controller has seen the
+// daemon set, and the status of this API version has no observed generation
+// to tell whether the controller has seen an update yet. So the desired
+// number of pods must also match the nodes the node selector and node name
+// of the template select.
+func daemonSetScheduled(c client.Interface, namespace, name string) wait.ConditionFunc {
+	return func() (bool, error) {
+		item, err := c.Extensions().DaemonSets(namespace).Get(name)
+		if err != nil {
+			return false, err
+		}
+
+		selector := labels.SelectorFromSet(labels.Set(item.Spec.Template.Spec.NodeSelector))
+		nodes, err := c.Nodes().List(api.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return false, err
+		}
+		desired := 0
+		for _, node := range nodes.Items {
+			if x := item.Spec.Template.Spec.NodeName; x != "" && x != node.Name {
+				continue
+			}
+			if nodeIsOutOfDisk(&node) {
+				// the controller doesn't run daemon pods there
+				continue
+			}
+			desired++
+		}
+
+		status := item.Status
+		return status.DesiredNumberScheduled == desired &&
+			status.CurrentNumberScheduled == desired &&
+			status.NumberMisscheduled == 0, nil
+	}
+}
+
+func nodeIsOutOfDisk(node *api.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == api.NodeOutOfDisk && c.Status == api.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func validateDaemonSetConfig(r *schema.ResourceData) field.ErrorList {
+	item := &extensions.DaemonSet{}
+	item.Namespace = r.Get("namespace").(string)
+	item.Name = r.Get("name").(string)
+
+	return validatePodTemplateObject(item, &item.ObjectMeta, &item.Spec.Template, func() error {
+		return writeDaemonSet(r, item)
+	})
+}
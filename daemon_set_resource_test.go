@@ -0,0 +1,122 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+)
+
+func TestDaemonSetScheduled(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+
+	for name, role := range map[string]string{"n1": "web", "n2": "db", "n3": "web"} {
+		node := &api.Node{}
+		node.Labels = map[string]string{"role": role}
+		if name == "n3" {
+			node.Status.Conditions = []api.NodeCondition{
+				{Type: api.NodeOutOfDisk, Status: api.ConditionTrue},
+			}
+		}
+		s.put("/api/v1/nodes/"+name, node)
+	}
+
+	cases := []struct {
+		NodeSelector map[string]string
+		NodeName     string
+		Status       extensions.DaemonSetStatus
+		Expected     bool
+	}{
+		{
+			NodeSelector: map[string]string{"role": "web"},
+			Status:       extensions.DaemonSetStatus{DesiredNumberScheduled: 1, CurrentNumberScheduled: 1},
+			Expected:     true,
+		},
+		{
+			NodeSelector: map[string]string{"role": "web"},
+			Status:       extensions.DaemonSetStatus{DesiredNumberScheduled: 1, CurrentNumberScheduled: 0},
+			Expected:     false,
+		},
+		// the status from before the node selector was narrowed
+		{
+			NodeSelector: map[string]string{"role": "web"},
+			Status:       extensions.DaemonSetStatus{DesiredNumberScheduled: 2, CurrentNumberScheduled: 2},
+			Expected:     false,
+		},
+		// the status from before the node selector was removed
+		{
+			Status:   extensions.DaemonSetStatus{DesiredNumberScheduled: 1, CurrentNumberScheduled: 1},
+			Expected: false,
+		},
+		{
+			Status:   extensions.DaemonSetStatus{DesiredNumberScheduled: 2, CurrentNumberScheduled: 2},
+			Expected: true,
+		},
+		{
+			Status:   extensions.DaemonSetStatus{DesiredNumberScheduled: 2, CurrentNumberScheduled: 2, NumberMisscheduled: 1},
+			Expected: false,
+		},
+		{
+			NodeName: "n2",
+			Status:   extensions.DaemonSetStatus{DesiredNumberScheduled: 1, CurrentNumberScheduled: 1},
+			Expected: true,
+		},
+		// the controller has not seen the daemon set yet
+		{
+			NodeSelector: map[string]string{"role": "web"},
+			Expected:     false,
+		},
+		{
+			NodeSelector: map[string]string{"role": "cache"},
+			Expected:     true,
+		},
+	}
+
+	for i, tc := range cases {
+		item := &extensions.DaemonSet{}
+		item.Spec.Template.Spec.NodeSelector = tc.NodeSelector
+		item.Spec.Template.Spec.NodeName = tc.NodeName
+		item.Status = tc.Status
+		s.put("/apis/extensions/v1beta1/namespaces/default/daemonsets/web", item)
+
+		actual, err := daemonSetScheduled(extractClient(s.meta()), "default", "web")()
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if actual != tc.Expected {
+			t.Fatalf("%d: bad: %v", i, actual)
+		}
+	}
+}
+
+// TestDaemonSetWaitTimeout creates a daemon set that is never scheduled.
+func TestDaemonSetWaitTimeout(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+	s.put("/api/v1/nodes/n1", &api.Node{})
+
+	raw := map[string]interface{}{
+		"name":         "web",
+		"wait_timeout": "1s",
+		"template": []interface{}{
+			map[string]interface{}{
+				"labels": map[string]interface{}{"app": "web"},
+				"container": []interface{}{
+					map[string]interface{}{"name": "web", "image": "web:1", "image_pull_policy": "IfNotPresent"},
+				},
+			},
+		},
+	}
+
+	start := time.Now()
+	_, err := apply(t, daemonSetResource(), nil, raw, s.meta())
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("err: %v", err)
+	}
+	if d := time.Since(start); d > 10*time.Second {
+		t.Fatalf("waited %s", d)
+	}
+}
@@ -0,0 +1,407 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/intstr"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+	"k8s.io/kubernetes/pkg/util/wait"
+)
+
+func deploymentResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "default",
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"annotations": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+
+			"replicas": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  1,
+			},
+			"strategy": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  string(extensions.RollingUpdateDeploymentStrategyType),
+				ValidateFunc: func(v interface{}, _ string) ([]string, []error) {
+					switch extensions.DeploymentStrategyType(v.(string)) {
+					case extensions.RollingUpdateDeploymentStrategyType, extensions.RecreateDeploymentStrategyType:
+						return nil, nil
+					}
+					return nil, []error{fmt.Errorf("invalid deployment strategy %q", v)}
+				},
+			},
+			"max_surge": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"max_unavailable": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"min_ready_seconds": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"revision_history_limit": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"delete_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "2m",
+				ValidateFunc: validateDuration,
+			},
+			"template": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem:     podTemplateResourceSpec,
+			},
+		},
+
+		Read:   resourceDeploymentRead,
+		Create: resourceDeploymentCreate,
+		Update: resourceDeploymentUpdate,
+		Delete: resourceDeploymentDelete,
+		Exists: resourceDeploymentExists,
+	}
+}
+
+func resourceDeploymentRead(r *schema.ResourceData, v interface{}) error {
+	client := extractClient(v)
+	namespace, name, err := split(r.Id())
+	if err != nil {
+		return err
+	}
+
+	item, err := client.Extensions().Deployments(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		r.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	readLabels(r, &item.ObjectMeta)
+	readAnnotations(r, &item.ObjectMeta)
+
+	r.Set("replicas", item.Spec.Replicas)
+	r.Set("strategy", string(item.Spec.Strategy.Type))
+	r.Set("min_ready_seconds", item.Spec.MinReadySeconds)
+
+	if x := item.Spec.Strategy.RollingUpdate; x != nil {
+		r.Set("max_surge", x.MaxSurge.String())
+		r.Set("max_unavailable", x.MaxUnavailable.String())
+	} else {
+		r.Set("max_surge", "")
+		r.Set("max_unavailable", "")
+	}
+
+	if item.Spec.RevisionHistoryLimit != nil {
+		r.Set("revision_history_limit", *item.Spec.RevisionHistoryLimit)
+	}
+
+	root := NewObjectBuilder(r, "")
+	t := root.NewList("template")
+	readPodTemplateSpec(t, &item.Spec.Template)
+	t.Apply()
+	return root.Apply()
+}
+
+func resourceDeploymentCreate(r *schema.ResourceData, v interface{}) error {
+	client := extractClient(v)
+	namespace := r.Get("namespace").(string)
+	name := r.Get("name").(string)
+
+	item := &extensions.Deployment{}
+	item.Name = name
+
+	err := writeDeployment(r, item)
+	if err != nil {
+		return err
+	}
+
+	err = writeLastApplied(client.ExtensionsClient.RESTClient, item)
+	if err != nil {
+		return err
+	}
+
+	item, err = client.Extensions().Deployments(namespace).Create(item)
+	if err != nil {
+		return err
+	}
+
+	r.SetId(join(namespace, name))
+	return resourceDeploymentRead(r, v)
+}
+
+func resourceDeploymentUpdate(r *schema.ResourceData, v interface{}) error {
+	client := extractClient(v)
+	namespace, name, err := split(r.Id())
+	if err != nil {
+		return err
+	}
+
+	err = patchObject(client.ExtensionsClient.RESTClient, "deployments", r, v, func(r *schema.ResourceData) (runtime.Object, error) {
+		item := &extensions.Deployment{}
+		item.Namespace = namespace
+		item.Name = name
+
+		err := writeDeployment(r, item)
+		return item, err
+	}, &extensions.Deployment{})
+	if err != nil {
+		return err
+	}
+
+	return resourceDeploymentRead(r, v)
+}
+
+func resourceDeploymentDelete(r *schema.ResourceData, v interface{}) error {
+	client := extractClient(v)
+	namespace, name, err := split(r.Id())
+	if err != nil {
+		return err
+	}
+	deployments := client.Extensions().Deployments(namespace)
+	replicaSets := client.Extensions().ReplicaSets(namespace)
+
+	timeout, err := readDuration(r, "delete_timeout", 2*time.Minute)
+	if err != nil {
+		return err
+	}
+
+	// Deleting a deployment doesn't cascade, so scale it down first and then
+	// remove the replica sets it leaves behind.
+	var item *extensions.Deployment
+	err = retryUpdate(func() (err error) {
+		item, err = deployments.Get(name)
+		if err != nil {
+			return err
+		}
+
+		item.Spec.Replicas = 0
+		item, err = deployments.Update(item)
+		return err
+	})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	selector, err := unversioned.LabelSelectorAsSelector(item.Spec.Selector)
+	if err != nil {
+		return err
+	}
+
+	err = wait.Poll(1*time.Second, timeout, func() (bool, error) {
+		item, err := deployments.Get(name)
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if item.Status.Replicas != 0 {
+			return false, nil
+		}
+
+		// the deployment status of this API version has no observed
+		// generation, the replica sets show whether the controller has
+		// scaled them down yet
+		list, err := replicaSets.List(api.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return false, err
+		}
+		for _, rs := range list.Items {
+			if rs.Spec.Replicas != 0 || rs.Status.ObservedGeneration < rs.Generation || rs.Status.Replicas != 0 {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = deployments.Delete(name, nil)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	list, err := replicaSets.List(api.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return err
+	}
+	for _, rs := range list.Items {
+		err := drainReplicaSet(replicaSets, rs.Name, timeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// drainReplicaSet scales a replica set that a deleted deployment left behind
+// down to zero and deletes it once its pods are gone.
+func drainReplicaSet(replicaSets client.ReplicaSetInterface, name string, timeout time.Duration) error {
+	err := retryUpdate(func() error {
+		rs, err := replicaSets.Get(name)
+		if err != nil {
+			return err
+		}
+
+		rs.Spec.Replicas = 0
+		_, err = replicaSets.Update(rs)
+		return err
+	})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = wait.Poll(1*time.Second, timeout, func() (bool, error) {
+		rs, err := replicaSets.Get(name)
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return rs.Status.Replicas == 0, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = replicaSets.Delete(name, nil)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func resourceDeploymentExists(r *schema.ResourceData, v interface{}) (bool, error) {
+	client := extractClient(v)
+	namespace, name, err := split(r.Id())
+	if err != nil {
+		return false, err
+	}
+
+	_, err = client.Extensions().Deployments(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func writeDeployment(r *schema.ResourceData, item *extensions.Deployment) error {
+	writeLabels(r, &item.ObjectMeta)
+	writeAnnotations(r, &item.ObjectMeta)
+
+	item.Spec.Replicas = r.Get("replicas").(int)
+	item.Spec.MinReadySeconds = r.Get("min_ready_seconds").(int)
+
+	item.Spec.RevisionHistoryLimit = nil
+	if x, ok := r.GetOk("revision_history_limit"); ok {
+		l := x.(int)
+		item.Spec.RevisionHistoryLimit = &l
+	}
+
+	item.Spec.Strategy = extensions.DeploymentStrategy{
+		Type: extensions.DeploymentStrategyType(r.Get("strategy").(string)),
+	}
+	if item.Spec.Strategy.Type == extensions.RollingUpdateDeploymentStrategyType {
+		item.Spec.Strategy.RollingUpdate = &extensions.RollingUpdateDeployment{
+			MaxSurge:       intstr.FromInt(1),
+			MaxUnavailable: intstr.FromInt(1),
+		}
+		if x, ok := r.GetOk("max_surge"); ok {
+			item.Spec.Strategy.RollingUpdate.MaxSurge = parseIntOrString(x.(string))
+		}
+		if x, ok := r.GetOk("max_unavailable"); ok {
+			item.Spec.Strategy.RollingUpdate.MaxUnavailable = parseIntOrString(x.(string))
+		}
+	}
+
+	item.Spec.Template = api.PodTemplateSpec{}
+	err := writePodTemplateSpec(r, &item.Spec.Template)
+	if err != nil {
+		return err
+	}
+
+	if len(item.Spec.Template.ObjectMeta.Labels) == 0 {
+		return fmt.Errorf("template labels must not be empty")
+	}
+	item.Spec.Selector = &unversioned.LabelSelector{
+		MatchLabels: item.Spec.Template.ObjectMeta.Labels,
+	}
+
+	return nil
+}
+
+func validateDeploymentConfig(r *schema.ResourceData) field.ErrorList {
+	item := &extensions.Deployment{}
+	item.Namespace = r.Get("namespace").(string)
+	item.Name = r.Get("name").(string)
+
+	return validatePodTemplateObject(item, &item.ObjectMeta, &item.Spec.Template, func() error {
+		return writeDeployment(r, item)
+	})
+}
+
+// parseIntOrString treats numeric strings as absolute values and everything
+// else (like "25%") as a string value.
+func parseIntOrString(s string) intstr.IntOrString {
+	if i, err := strconv.Atoi(s); err == nil {
+		return intstr.FromInt(i)
+	}
+	return intstr.FromString(s)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"path"
+	"strings"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/util/intstr"
+)
+
+func TestDeploymentReadStrategy(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+
+	cases := []struct {
+		Strategy       extensions.DeploymentStrategy
+		MaxSurge       string
+		MaxUnavailable string
+	}{
+		{
+			Strategy: extensions.DeploymentStrategy{
+				Type: extensions.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &extensions.RollingUpdateDeployment{
+					MaxSurge:       intstr.FromString("25%"),
+					MaxUnavailable: intstr.FromInt(0),
+				},
+			},
+			MaxSurge:       "25%",
+			MaxUnavailable: "0",
+		},
+		// the values of a rolling update are cleared when the strategy
+		// changes to recreate
+		{
+			Strategy: extensions.DeploymentStrategy{
+				Type: extensions.RecreateDeploymentStrategyType,
+			},
+		},
+	}
+
+	res := deploymentResource()
+	for i, tc := range cases {
+		item := &extensions.Deployment{}
+		item.Spec.Strategy = tc.Strategy
+		s.put("/apis/extensions/v1beta1/namespaces/default/deployments/web", item)
+
+		r := resourceData(t, res, "default/web", map[string]string{
+			"namespace":       "default",
+			"name":            "web",
+			"strategy":        "RollingUpdate",
+			"max_surge":       "1",
+			"max_unavailable": "1",
+		})
+		err := res.Read(r, s.meta())
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		if x := r.Get("max_surge").(string); x != tc.MaxSurge {
+			t.Fatalf("%d: bad max_surge: %q", i, x)
+		}
+		if x := r.Get("max_unavailable").(string); x != tc.MaxUnavailable {
+			t.Fatalf("%d: bad max_unavailable: %q", i, x)
+		}
+	}
+}
+
+// deploymentManager is a react hook for the fake server that scales the
+// replica sets of a deployment with it and updates their status.
+func deploymentManager(s *fakeServer, p string, obj map[string]interface{}) {
+	if obj == nil {
+		return
+	}
+	spec := obj["spec"].(map[string]interface{})
+	meta := obj["metadata"].(map[string]interface{})
+
+	switch path.Base(path.Dir(p)) {
+	case "deployments":
+		replicaSets := path.Join(path.Dir(path.Dir(p)), "replicasets")
+		for k, rs := range s.objects {
+			if path.Dir(k) != replicaSets {
+				continue
+			}
+			rs = copyJSON(rs)
+			rs["spec"].(map[string]interface{})["replicas"] = spec["replicas"]
+			s.store(k, rs)
+		}
+	case "replicasets":
+		obj["status"] = map[string]interface{}{
+			"replicas":           spec["replicas"],
+			"observedGeneration": meta["generation"],
+		}
+	}
+}
+
+// TestDeploymentDelete deletes a deployment whose status claims it has no
+// replicas left while its replica set has not been scaled down yet. The
+// deployment is kept until the replica set is scaled down.
+func TestDeploymentDelete(t *testing.T) {
+	for i, scaled := range []bool{false, true} {
+		s := newFakeServer(t)
+		if scaled {
+			s.react = deploymentManager
+		}
+
+		selector := &unversioned.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+		item := &extensions.Deployment{}
+		item.Spec.Replicas = 2
+		item.Spec.Selector = selector
+		s.put("/apis/extensions/v1beta1/namespaces/default/deployments/web", item)
+
+		rs := &extensions.ReplicaSet{}
+		rs.Labels = selector.MatchLabels
+		rs.Spec.Replicas = 2
+		rs.Spec.Selector = selector
+		rs.Status.Replicas = 2
+		s.put("/apis/extensions/v1beta1/namespaces/default/replicasets/web-1", rs)
+
+		res := deploymentResource()
+		r := resourceData(t, res, "default/web", map[string]string{
+			"namespace":      "default",
+			"name":           "web",
+			"delete_timeout": "2s",
+		})
+		err := res.Delete(r, s.meta())
+		l := s.list("/apis/extensions/v1beta1/namespaces/default/replicasets")
+		exists := s.get("/apis/extensions/v1beta1/namespaces/default/deployments/web", &extensions.Deployment{})
+		s.Close()
+
+		if !scaled {
+			if err == nil || !strings.Contains(err.Error(), "timed out") {
+				t.Fatalf("%d: err: %v", i, err)
+			}
+			if !exists {
+				t.Fatalf("%d: the deployment was deleted", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if len(l) != 0 {
+			t.Fatalf("%d: bad replica sets: %v", i, l)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+)
+
+func horizontalPodAutoscalerResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "default",
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"annotations": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"scale_target_ref": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"kind": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "ReplicationController",
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"api_version": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "v1",
+						},
+					},
+				},
+			},
+			"min_replicas": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  1,
+			},
+			"max_replicas": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"target_cpu_utilization_percentage": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
+			"current_replicas": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"desired_replicas": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"current_cpu_utilization_percentage": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+		Create: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace := r.Get("namespace").(string)
+			name := r.Get("name").(string)
+
+			item := &extensions.HorizontalPodAutoscaler{}
+			item.Name = name
+			writeHorizontalPodAutoscaler(r, item)
+
+			err := writeLastApplied(client.ExtensionsClient.RESTClient, item)
+			if err != nil {
+				return err
+			}
+
+			item, err = client.Extensions().HorizontalPodAutoscalers(namespace).Create(item)
+			if err != nil {
+				return err
+			}
+
+			r.SetId(join(namespace, name))
+			readHorizontalPodAutoscalerStatus(r, &item.Status)
+			return nil
+		},
+		Read: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			item, err := client.Extensions().HorizontalPodAutoscalers(namespace).Get(name)
+			if errors.IsNotFound(err) {
+				r.SetId("")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			readLabels(r, &item.ObjectMeta)
+			readAnnotations(r, &item.ObjectMeta)
+			readHorizontalPodAutoscalerStatus(r, &item.Status)
+
+			r.Set("name", item.ObjectMeta.Name)
+			r.Set("scale_target_ref", []interface{}{map[string]interface{}{
+				"kind":        item.Spec.ScaleRef.Kind,
+				"name":        item.Spec.ScaleRef.Name,
+				"api_version": item.Spec.ScaleRef.APIVersion,
+			}})
+			if item.Spec.MinReplicas != nil {
+				r.Set("min_replicas", *item.Spec.MinReplicas)
+			}
+			r.Set("max_replicas", item.Spec.MaxReplicas)
+			if item.Spec.CPUUtilization != nil {
+				r.Set("target_cpu_utilization_percentage", item.Spec.CPUUtilization.TargetPercentage)
+			}
+			return nil
+		},
+		Update: func(r *schema.ResourceData, v interface{}) error {
+
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			item := &extensions.HorizontalPodAutoscaler{}
+			err = patchObject(client.ExtensionsClient.RESTClient, "horizontalpodautoscalers", r, v, func(r *schema.ResourceData) (runtime.Object, error) {
+				item := &extensions.HorizontalPodAutoscaler{}
+				item.Namespace = namespace
+				item.Name = name
+
+				writeHorizontalPodAutoscaler(r, item)
+				return item, nil
+			}, item)
+			if err != nil {
+				return err
+			}
+
+			readHorizontalPodAutoscalerStatus(r, &item.Status)
+			return nil
+		},
+		Delete: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			return client.Extensions().HorizontalPodAutoscalers(namespace).Delete(name, nil)
+		},
+		Exists: func(r *schema.ResourceData, v interface{}) (bool, error) {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return false, err
+			}
+
+			_, err = client.Extensions().HorizontalPodAutoscalers(namespace).Get(name)
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			return true, nil
+		},
+	}
+}
+
+func writeHorizontalPodAutoscaler(r *schema.ResourceData, item *extensions.HorizontalPodAutoscaler) {
+	writeLabels(r, &item.ObjectMeta)
+	writeAnnotations(r, &item.ObjectMeta)
+
+	item.Spec.ScaleRef = extensions.SubresourceReference{Subresource: "scale"}
+	if m, ok := extractSingleMap(r.Get("scale_target_ref")); ok && m != nil {
+		item.Spec.ScaleRef.Kind, _ = m["kind"].(string)
+		item.Spec.ScaleRef.Name, _ = m["name"].(string)
+		item.Spec.ScaleRef.APIVersion, _ = m["api_version"].(string)
+	}
+
+	minReplicas := r.Get("min_replicas").(int)
+	item.Spec.MinReplicas = &minReplicas
+	item.Spec.MaxReplicas = r.Get("max_replicas").(int)
+
+	item.Spec.CPUUtilization = nil
+	if x, ok := r.GetOk("target_cpu_utilization_percentage"); ok {
+		item.Spec.CPUUtilization = &extensions.CPUTargetUtilization{
+			TargetPercentage: x.(int),
+		}
+	}
+}
+
+func readHorizontalPodAutoscalerStatus(r *schema.ResourceData, status *extensions.HorizontalPodAutoscalerStatus) {
+	r.Set("current_replicas", status.CurrentReplicas)
+	r.Set("desired_replicas", status.DesiredReplicas)
+	if status.CurrentCPUUtilizationPercentage != nil {
+		r.Set("current_cpu_utilization_percentage", *status.CurrentCPUUtilizationPercentage)
+	}
+}
+
+func validateHorizontalPodAutoscalerConfig(r *schema.ResourceData) field.ErrorList {
+	item := &extensions.HorizontalPodAutoscaler{}
+	item.Namespace = r.Get("namespace").(string)
+	item.Name = r.Get("name").(string)
+	writeHorizontalPodAutoscaler(r, item)
+
+	return validateExtensionsObjectMeta(&item.ObjectMeta)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+
+	"k8s.io/kubernetes/pkg/apis/extensions"
+)
+
+// TestHorizontalPodAutoscalerApply creates and updates an autoscaler and
+// reads the status the autoscaler controller reports.
+func TestHorizontalPodAutoscalerApply(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+
+	config := func(max int) map[string]interface{} {
+		return map[string]interface{}{
+			"name": "web",
+			"scale_target_ref": []interface{}{
+				map[string]interface{}{"name": "web"},
+			},
+			"min_replicas":                      2,
+			"max_replicas":                      max,
+			"target_cpu_utilization_percentage": 70,
+		}
+	}
+
+	res := providerResource(horizontalPodAutoscalerResource())
+	state, err := apply(t, res, nil, config(5), s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	const p = "/apis/extensions/v1beta1/namespaces/default/horizontalpodautoscalers/web"
+	item := &extensions.HorizontalPodAutoscaler{}
+	if !s.get(p, item) {
+		t.Fatalf("%s is missing", p)
+	}
+	ref := extensions.SubresourceReference{Kind: "ReplicationController", Name: "web", APIVersion: "v1", Subresource: "scale"}
+	if item.Spec.ScaleRef != ref {
+		t.Fatalf("bad scale ref: %#v", item.Spec.ScaleRef)
+	}
+	if item.Spec.MinReplicas == nil || *item.Spec.MinReplicas != 2 || item.Spec.MaxReplicas != 5 {
+		t.Fatalf("bad replicas: %v %d", item.Spec.MinReplicas, item.Spec.MaxReplicas)
+	}
+	if item.Spec.CPUUtilization == nil || item.Spec.CPUUtilization.TargetPercentage != 70 {
+		t.Fatalf("bad cpu utilization: %#v", item.Spec.CPUUtilization)
+	}
+
+	utilization := 85
+	item.Status = extensions.HorizontalPodAutoscalerStatus{
+		CurrentReplicas:                 3,
+		DesiredReplicas:                 4,
+		CurrentCPUUtilizationPercentage: &utilization,
+	}
+	s.put(p, item)
+
+	state, err = res.Refresh(state, s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for k, v := range map[string]string{
+		"current_replicas":                   "3",
+		"desired_replicas":                   "4",
+		"current_cpu_utilization_percentage": "85",
+	} {
+		if state.Attributes[k] != v {
+			t.Fatalf("bad %s: %q", k, state.Attributes[k])
+		}
+	}
+
+	_, err = apply(t, res, state, config(8), s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	item = &extensions.HorizontalPodAutoscaler{}
+	s.get(p, item)
+	if item.Spec.MaxReplicas != 8 {
+		t.Fatalf("bad max replicas: %d", item.Spec.MaxReplicas)
+	}
+}
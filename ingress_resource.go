@@ -0,0 +1,347 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+)
+
+var ingressBackendResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"service_name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"service_port": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+	},
+}
+
+func ingressResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "default",
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"annotations": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"backend": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     ingressBackendResourceSpec,
+			},
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"http": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"path": {
+										Type:     schema.TypeList,
+										Required: true,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"path": {
+													Type:     schema.TypeString,
+													Optional: true,
+												},
+												"backend": {
+													Type:     schema.TypeList,
+													Required: true,
+													Elem:     ingressBackendResourceSpec,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"tls": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"hosts": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"secret_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+
+			"load_balancer_ingress": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"hostname": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+		Create: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace := r.Get("namespace").(string)
+			name := r.Get("name").(string)
+
+			item := &extensions.Ingress{}
+			item.Name = name
+			writeIngress(r, item)
+
+			err := writeLastApplied(client.ExtensionsClient.RESTClient, item)
+			if err != nil {
+				return err
+			}
+
+			item, err = client.Extensions().Ingress(namespace).Create(item)
+			if err != nil {
+				return err
+			}
+
+			r.SetId(join(namespace, name))
+			readLoadBalancerIngressIPs(r, &item.Status.LoadBalancer)
+			return nil
+		},
+		Read: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			item, err := client.Extensions().Ingress(namespace).Get(name)
+			if errors.IsNotFound(err) {
+				r.SetId("")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			readLabels(r, &item.ObjectMeta)
+			readAnnotations(r, &item.ObjectMeta)
+			readIngressSpec(r, &item.Spec)
+			readLoadBalancerIngressIPs(r, &item.Status.LoadBalancer)
+
+			r.Set("name", item.ObjectMeta.Name)
+			return nil
+		},
+		Update: func(r *schema.ResourceData, v interface{}) error {
+
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			item := &extensions.Ingress{}
+			err = patchObject(client.ExtensionsClient.RESTClient, "ingresses", r, v, func(r *schema.ResourceData) (runtime.Object, error) {
+				item := &extensions.Ingress{}
+				item.Namespace = namespace
+				item.Name = name
+
+				writeIngress(r, item)
+				return item, nil
+			}, item)
+			if err != nil {
+				return err
+			}
+
+			readLoadBalancerIngressIPs(r, &item.Status.LoadBalancer)
+			return nil
+		},
+		Delete: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			return client.Extensions().Ingress(namespace).Delete(name, nil)
+		},
+		Exists: func(r *schema.ResourceData, v interface{}) (bool, error) {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return false, err
+			}
+
+			_, err = client.Extensions().Ingress(namespace).Get(name)
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			return true, nil
+		},
+	}
+}
+
+func readIngressBackend(b *extensions.IngressBackend) []interface{} {
+	if b == nil {
+		return nil
+	}
+	return []interface{}{map[string]interface{}{
+		"service_name": b.ServiceName,
+		"service_port": b.ServicePort.String(),
+	}}
+}
+
+func writeIngressBackend(v interface{}) *extensions.IngressBackend {
+	m, ok := extractSingleMap(v)
+	if !ok || m == nil {
+		return nil
+	}
+	serviceName, _ := m["service_name"].(string)
+	servicePort, _ := m["service_port"].(string)
+	return &extensions.IngressBackend{
+		ServiceName: serviceName,
+		ServicePort: parseIntOrString(servicePort),
+	}
+}
+
+func readIngressSpec(r *schema.ResourceData, spec *extensions.IngressSpec) {
+	r.Set("backend", readIngressBackend(spec.Backend))
+
+	var rules []interface{}
+	for _, rule := range spec.Rules {
+		m := map[string]interface{}{
+			"host": rule.Host,
+		}
+		if rule.HTTP != nil {
+			var paths []interface{}
+			for _, p := range rule.HTTP.Paths {
+				paths = append(paths, map[string]interface{}{
+					"path":    p.Path,
+					"backend": readIngressBackend(&p.Backend),
+				})
+			}
+			m["http"] = []interface{}{map[string]interface{}{
+				"path": paths,
+			}}
+		}
+		rules = append(rules, m)
+	}
+	r.Set("rule", rules)
+
+	var tls []interface{}
+	for _, t := range spec.TLS {
+		var hosts []interface{}
+		for _, h := range t.Hosts {
+			hosts = append(hosts, h)
+		}
+		tls = append(tls, map[string]interface{}{
+			"hosts":       hosts,
+			"secret_name": t.SecretName,
+		})
+	}
+	r.Set("tls", tls)
+}
+
+func writeIngress(r *schema.ResourceData, item *extensions.Ingress) {
+	writeLabels(r, &item.ObjectMeta)
+	writeAnnotations(r, &item.ObjectMeta)
+
+	item.Spec.Backend = writeIngressBackend(r.Get("backend"))
+
+	item.Spec.Rules = nil
+	if l, _ := r.Get("rule").([]interface{}); l != nil {
+		for _, v := range l {
+			m := v.(map[string]interface{})
+			rule := extensions.IngressRule{}
+			rule.Host, _ = m["host"].(string)
+
+			if http, ok := extractSingleMap(m["http"]); ok && http != nil {
+				rule.HTTP = &extensions.HTTPIngressRuleValue{}
+				paths, _ := http["path"].([]interface{})
+				for _, p := range paths {
+					pm := p.(map[string]interface{})
+					path := extensions.HTTPIngressPath{}
+					path.Path, _ = pm["path"].(string)
+					if b := writeIngressBackend(pm["backend"]); b != nil {
+						path.Backend = *b
+					}
+					rule.HTTP.Paths = append(rule.HTTP.Paths, path)
+				}
+			}
+
+			item.Spec.Rules = append(item.Spec.Rules, rule)
+		}
+	}
+
+	item.Spec.TLS = nil
+	if l, _ := r.Get("tls").([]interface{}); l != nil {
+		for _, v := range l {
+			m := v.(map[string]interface{})
+			tls := extensions.IngressTLS{}
+			tls.SecretName, _ = m["secret_name"].(string)
+			hosts, _ := m["hosts"].([]interface{})
+			for _, h := range hosts {
+				tls.Hosts = append(tls.Hosts, h.(string))
+			}
+			item.Spec.TLS = append(item.Spec.TLS, tls)
+		}
+	}
+}
+
+func validateIngressConfig(r *schema.ResourceData) field.ErrorList {
+	item := &extensions.Ingress{}
+	item.Namespace = r.Get("namespace").(string)
+	item.Name = r.Get("name").(string)
+	writeIngress(r, item)
+
+	return validateExtensionsObjectMeta(&item.ObjectMeta)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/util/intstr"
+)
+
+// TestIngressRoundTrip reads the spec of an ingress into the state and
+// writes it back.
+func TestIngressRoundTrip(t *testing.T) {
+	spec := extensions.IngressSpec{
+		Backend: &extensions.IngressBackend{ServiceName: "default", ServicePort: intstr.FromInt(80)},
+		TLS: []extensions.IngressTLS{
+			{Hosts: []string{"example.com", "www.example.com"}, SecretName: "example-tls"},
+			{SecretName: "wildcard-tls"},
+		},
+		Rules: []extensions.IngressRule{
+			{Host: "example.com", IngressRuleValue: extensions.IngressRuleValue{
+				HTTP: &extensions.HTTPIngressRuleValue{Paths: []extensions.HTTPIngressPath{
+					{Path: "/api", Backend: extensions.IngressBackend{ServiceName: "api", ServicePort: intstr.FromString("http")}},
+					{Backend: extensions.IngressBackend{ServiceName: "web", ServicePort: intstr.FromInt(8080)}},
+				}},
+			}},
+			{Host: "empty.example.com"},
+		},
+	}
+
+	r := resourceData(t, ingressResource(), "default/web", nil)
+	readIngressSpec(r, &spec)
+
+	item := &extensions.Ingress{}
+	writeIngress(r, item)
+	if !api.Semantic.DeepEqual(item.Spec, spec) {
+		t.Fatalf("expected %#v, got %#v", spec, item.Spec)
+	}
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+	"k8s.io/kubernetes/pkg/util/wait"
+)
+
+// jobNameLabel is added to the pod template to select the pods of a job.
+const jobNameLabel = "job-name"
+
+func jobResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "default",
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"annotations": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+
+			"parallelism": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  1,
+			},
+			"completions": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				ForceNew: true,
+				Default:  1,
+			},
+			"active_deadline_seconds": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				ForceNew: true,
+			},
+			"template": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				Elem:     podTemplateResourceSpec,
+			},
+			"wait_for_completion": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"wait_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "5m",
+				ValidateFunc: validateDuration,
+			},
+
+			"active": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"succeeded": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"failed": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+
+		Read:   resourceJobRead,
+		Create: resourceJobCreate,
+		Update: resourceJobUpdate,
+		Delete: resourceJobDelete,
+		Exists: resourceJobExists,
+	}
+}
+
+func resourceJobRead(r *schema.ResourceData, v interface{}) error {
+	client := extractClient(v)
+	namespace, name, err := split(r.Id())
+	if err != nil {
+		return err
+	}
+
+	item, err := client.Extensions().Jobs(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		r.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	readLabels(r, &item.ObjectMeta)
+	readAnnotations(r, &item.ObjectMeta)
+
+	if item.Spec.Parallelism != nil {
+		r.Set("parallelism", *item.Spec.Parallelism)
+	}
+	if item.Spec.Completions != nil {
+		r.Set("completions", *item.Spec.Completions)
+	}
+	if item.Spec.ActiveDeadlineSeconds != nil {
+		r.Set("active_deadline_seconds", int(*item.Spec.ActiveDeadlineSeconds))
+	}
+
+	r.Set("active", item.Status.Active)
+	r.Set("succeeded", item.Status.Succeeded)
+	r.Set("failed", item.Status.Failed)
+
+	root := NewObjectBuilder(r, "")
+	t := root.NewList("template")
+	delete(item.Spec.Template.ObjectMeta.Labels, jobNameLabel)
+	readPodTemplateSpec(t, &item.Spec.Template)
+	t.Apply()
+	return root.Apply()
+}
+
+func resourceJobCreate(r *schema.ResourceData, v interface{}) error {
+	client := extractClient(v)
+	namespace := r.Get("namespace").(string)
+	name := r.Get("name").(string)
+
+	item := &extensions.Job{}
+	item.Name = name
+
+	err := writeJob(r, item)
+	if err != nil {
+		return err
+	}
+
+	err = writeLastApplied(client.ExtensionsClient.RESTClient, item)
+	if err != nil {
+		return err
+	}
+
+	item, err = client.Extensions().Jobs(namespace).Create(item)
+	if err != nil {
+		return err
+	}
+
+	r.SetId(join(namespace, name))
+
+	if r.Get("wait_for_completion").(bool) {
+		err = waitForJob(client, namespace, name, r.Get("wait_timeout").(string))
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceJobRead(r, v)
+}
+
+func resourceJobUpdate(r *schema.ResourceData, v interface{}) error {
+	client := extractClient(v)
+	namespace, name, err := split(r.Id())
+	if err != nil {
+		return err
+	}
+
+	// everything but the parallelism, labels and annotations is immutable
+	if !r.HasChange("parallelism") && !r.HasChange("labels") && !r.HasChange("annotations") {
+		return nil
+	}
+
+	err = patchObject(client.ExtensionsClient.RESTClient, "jobs", r, v, func(r *schema.ResourceData) (runtime.Object, error) {
+		item := &extensions.Job{}
+		item.Namespace = namespace
+		item.Name = name
+
+		err := writeJob(r, item)
+		return item, err
+	}, &extensions.Job{})
+	if err != nil {
+		return err
+	}
+
+	return resourceJobRead(r, v)
+}
+
+func resourceJobDelete(r *schema.ResourceData, v interface{}) error {
+	client := extractClient(v)
+	namespace, name, err := split(r.Id())
+	if err != nil {
+		return err
+	}
+	jobs := client.Extensions().Jobs(namespace)
+	pods := client.Pods(namespace)
+
+	// Deleting a job doesn't cascade, so stop it from starting new pods and
+	// then remove the pods it leaves behind.
+	var item *extensions.Job
+	err = retryUpdate(func() (err error) {
+		item, err = jobs.Get(name)
+		if err != nil {
+			return err
+		}
+
+		zero := 0
+		item.Spec.Parallelism = &zero
+		item, err = jobs.Update(item)
+		return err
+	})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	selector, err := unversioned.LabelSelectorAsSelector(item.Spec.Selector)
+	if err != nil {
+		return err
+	}
+
+	err = jobs.Delete(name, nil)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	list, err := pods.List(api.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return err
+	}
+	for _, pod := range list.Items {
+		err := pods.Delete(pod.Name, nil)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func resourceJobExists(r *schema.ResourceData, v interface{}) (bool, error) {
+	client := extractClient(v)
+	namespace, name, err := split(r.Id())
+	if err != nil {
+		return false, err
+	}
+
+	_, err = client.Extensions().Jobs(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func writeJob(r *schema.ResourceData, item *extensions.Job) error {
+	writeLabels(r, &item.ObjectMeta)
+	writeAnnotations(r, &item.ObjectMeta)
+
+	parallelism := r.Get("parallelism").(int)
+	completions := r.Get("completions").(int)
+	item.Spec.Parallelism = &parallelism
+	item.Spec.Completions = &completions
+
+	if x, ok := r.GetOk("active_deadline_seconds"); ok {
+		l := int64(x.(int))
+		item.Spec.ActiveDeadlineSeconds = &l
+	}
+
+	err := writePodTemplateSpec(r, &item.Spec.Template)
+	if err != nil {
+		return err
+	}
+
+	// pods of a job must not be restarted forever
+	if item.Spec.Template.Spec.RestartPolicy == "" {
+		item.Spec.Template.Spec.RestartPolicy = api.RestartPolicyOnFailure
+	}
+
+	if item.Spec.Template.ObjectMeta.Labels == nil {
+		item.Spec.Template.ObjectMeta.Labels = make(map[string]string)
+	}
+	item.Spec.Template.ObjectMeta.Labels[jobNameLabel] = item.Name
+	item.Spec.Selector = &unversioned.LabelSelector{
+		MatchLabels: map[string]string{jobNameLabel: item.Name},
+	}
+
+	return nil
+}
+
+// waitForJob waits until the job either completed or failed. A failed job is
+// reported with the termination messages of its failed pods.
+func waitForJob(c client.Interface, namespace, name, timeout string) error {
+	d, err := time.ParseDuration(timeout)
+	if err != nil {
+		return err
+	}
+
+	var failed *extensions.JobCondition
+	err = wait.Poll(1*time.Second, d, func() (bool, error) {
+		item, err := c.Extensions().Jobs(namespace).Get(name)
+		if err != nil {
+			return false, err
+		}
+		for i, cond := range item.Status.Conditions {
+			if cond.Status != api.ConditionTrue {
+				continue
+			}
+			switch cond.Type {
+			case extensions.JobComplete:
+				return true, nil
+			case extensions.JobFailed:
+				failed = &item.Status.Conditions[i]
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("job %q did not complete within %s", name, timeout)
+	}
+	if err != nil {
+		return err
+	}
+	if failed == nil {
+		return nil
+	}
+
+	msgs := []string{fmt.Sprintf("job %q failed: %s %s", name, failed.Reason, failed.Message)}
+
+	pods, err := c.Pods(namespace).List(api.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{jobNameLabel: name}),
+	})
+	if err != nil {
+		return fmt.Errorf("%s (%s)", msgs[0], err)
+	}
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			t := status.State.Terminated
+			if t == nil {
+				t = status.LastTerminationState.Terminated
+			}
+			if t == nil || t.ExitCode == 0 {
+				continue
+			}
+			msgs = append(msgs, fmt.Sprintf("pod %s, container %s exited with %d: %s %s",
+				pod.Name, status.Name, t.ExitCode, t.Reason, t.Message))
+		}
+	}
+
+	return fmt.Errorf("%s", strings.Join(msgs, "\n"))
+}
+
+func validateJobConfig(r *schema.ResourceData) field.ErrorList {
+	item := &extensions.Job{}
+	item.Namespace = r.Get("namespace").(string)
+	item.Name = r.Get("name").(string)
+
+	return validatePodTemplateObject(item, &item.ObjectMeta, &item.Spec.Template, func() error {
+		return writeJob(r, item)
+	})
+}
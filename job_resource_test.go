@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+)
+
+func TestWaitForJob(t *testing.T) {
+	cases := []struct {
+		Conditions []extensions.JobCondition
+		Expected   string
+	}{
+		{
+			Conditions: []extensions.JobCondition{
+				{Type: extensions.JobComplete, Status: api.ConditionTrue},
+			},
+		},
+		{
+			Conditions: []extensions.JobCondition{
+				{Type: extensions.JobFailed, Status: api.ConditionTrue, Reason: "DeadlineExceeded", Message: "Job was active longer than specified deadline"},
+			},
+			Expected: `job "migrate" failed: DeadlineExceeded Job was active longer than specified deadline` + "\n" +
+				`pod migrate-1, container migrate exited with 2: Error no such table`,
+		},
+		{
+			Conditions: []extensions.JobCondition{
+				{Type: extensions.JobComplete, Status: api.ConditionFalse},
+			},
+			Expected: `job "migrate" did not complete within 1s`,
+		},
+	}
+
+	for i, tc := range cases {
+		s := newFakeServer(t)
+
+		item := &extensions.Job{}
+		item.Status.Conditions = tc.Conditions
+		s.put("/apis/extensions/v1beta1/namespaces/default/jobs/migrate", item)
+
+		for name, code := range map[string]int{"migrate-1": 2, "migrate-2": 0} {
+			pod := &api.Pod{}
+			pod.Labels = map[string]string{jobNameLabel: "migrate"}
+			pod.Status.ContainerStatuses = []api.ContainerStatus{{
+				Name: "migrate",
+				State: api.ContainerState{
+					Terminated: &api.ContainerStateTerminated{ExitCode: code, Reason: "Error", Message: "no such table"},
+				},
+			}}
+			s.put("/api/v1/namespaces/default/pods/"+name, pod)
+		}
+
+		err := waitForJob(extractClient(s.meta()), "default", "migrate", "1s")
+		s.Close()
+
+		actual := ""
+		if err != nil {
+			actual = err.Error()
+		}
+		if actual != tc.Expected {
+			t.Fatalf("%d: bad: %q", i, actual)
+		}
+	}
+}
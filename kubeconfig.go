@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/mitchellh/go-homedir"
+
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+)
+
+// kubeconfig mirrors the subset of the kubectl config file format that is
+// needed to build a client configuration.
+type kubeconfig struct {
+	CurrentContext string `json:"current-context"`
+	Clusters       []struct {
+		Name    string `json:"name"`
+		Cluster struct {
+			Server                   string `json:"server"`
+			InsecureSkipTLSVerify    bool   `json:"insecure-skip-tls-verify"`
+			CertificateAuthority     string `json:"certificate-authority"`
+			CertificateAuthorityData []byte `json:"certificate-authority-data"`
+		} `json:"cluster"`
+	} `json:"clusters"`
+	Users []struct {
+		Name string `json:"name"`
+		User struct {
+			ClientCertificate     string `json:"client-certificate"`
+			ClientCertificateData []byte `json:"client-certificate-data"`
+			ClientKey             string `json:"client-key"`
+			ClientKeyData         []byte `json:"client-key-data"`
+			Token                 string `json:"token"`
+			Username              string `json:"username"`
+			Password              string `json:"password"`
+		} `json:"user"`
+	} `json:"users"`
+	Contexts []struct {
+		Name    string `json:"name"`
+		Context struct {
+			Cluster string `json:"cluster"`
+			User    string `json:"user"`
+		} `json:"context"`
+	} `json:"contexts"`
+}
+
+// loadKubeconfig reads the kubeconfig file at path and applies the cluster and
+// user of the named context (or the current context when blank) to config.
+func loadKubeconfig(config *client.Config, path, context string) error {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var kc kubeconfig
+	err = yaml.Unmarshal(data, &kc)
+	if err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+
+	if context == "" {
+		context = kc.CurrentContext
+	}
+	if context == "" {
+		return fmt.Errorf("%s: no context selected", path)
+	}
+
+	var clusterName, userName string
+	found := false
+	for _, c := range kc.Contexts {
+		if c.Name == context {
+			clusterName, userName = c.Context.Cluster, c.Context.User
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("%s: context %q not found", path, context)
+	}
+
+	// relative paths in a kubeconfig are relative to the file itself
+	dir := filepath.Dir(path)
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(dir, p)
+	}
+
+	for _, c := range kc.Clusters {
+		if c.Name != clusterName {
+			continue
+		}
+		config.Host = c.Cluster.Server
+		config.Insecure = c.Cluster.InsecureSkipTLSVerify
+		config.CAFile = resolve(c.Cluster.CertificateAuthority)
+		config.CAData = c.Cluster.CertificateAuthorityData
+	}
+
+	for _, u := range kc.Users {
+		if u.Name != userName {
+			continue
+		}
+		config.CertFile = resolve(u.User.ClientCertificate)
+		config.CertData = u.User.ClientCertificateData
+		config.KeyFile = resolve(u.User.ClientKey)
+		config.KeyData = u.User.ClientKeyData
+		config.BearerToken = u.User.Token
+		config.Username = u.User.Username
+		config.Password = u.User.Password
+	}
+
+	return nil
+}
+
+// pemOrFile returns the PEM data when s looks like a PEM block and treats it
+// as a file path otherwise.
+func pemOrFile(s string) (data []byte, file string) {
+	if strings.Contains(s, "-----BEGIN") {
+		return []byte(s), ""
+	}
+	if p, err := homedir.Expand(s); err == nil {
+		s = p
+	}
+	return nil, s
+}
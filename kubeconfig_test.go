@@ -0,0 +1,134 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+)
+
+const testKubeconfig = `
+current-context: dev
+clusters:
+- name: dev-cluster
+  cluster:
+    server: https://dev.example.com
+    certificate-authority: ca.pem
+- name: prod-cluster
+  cluster:
+    server: https://prod.example.com
+    insecure-skip-tls-verify: true
+users:
+- name: dev-user
+  user:
+    client-certificate: /etc/kubernetes/cert.pem
+    client-key: key.pem
+- name: prod-user
+  user:
+    token: secret
+contexts:
+- name: dev
+  context:
+    cluster: dev-cluster
+    user: dev-user
+- name: prod
+  context:
+    cluster: prod-cluster
+    user: prod-user
+`
+
+func TestLoadKubeconfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+	err = ioutil.WriteFile(path, []byte(testKubeconfig), 0600)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	empty := filepath.Join(dir, "empty")
+	err = ioutil.WriteFile(empty, []byte("clusters: []\n"), 0600)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cases := []struct {
+		Path    string
+		Context string
+		Config  client.Config
+		Err     bool
+	}{
+		{
+			Path: path,
+			Config: client.Config{
+				Host:     "https://dev.example.com",
+				CAFile:   filepath.Join(dir, "ca.pem"),
+				CertFile: "/etc/kubernetes/cert.pem",
+				KeyFile:  filepath.Join(dir, "key.pem"),
+			},
+		},
+		{
+			Path:    path,
+			Context: "prod",
+			Config: client.Config{
+				Host:        "https://prod.example.com",
+				Insecure:    true,
+				BearerToken: "secret",
+			},
+		},
+		{
+			Path:    path,
+			Context: "staging",
+			Err:     true,
+		},
+		{
+			Path: empty,
+			Err:  true,
+		},
+		{
+			Path: filepath.Join(dir, "missing"),
+			Err:  true,
+		},
+	}
+
+	for i, tc := range cases {
+		var config client.Config
+		err := loadKubeconfig(&config, tc.Path, tc.Context)
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: err: %v", i, err)
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(config, tc.Config) {
+			t.Fatalf("%d: bad: %#v", i, config)
+		}
+	}
+}
+
+func TestPemOrFile(t *testing.T) {
+	pem := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+
+	cases := []struct {
+		Input string
+		Data  []byte
+		File  string
+	}{
+		{pem, []byte(pem), ""},
+		{"/etc/kubernetes/ca.pem", nil, "/etc/kubernetes/ca.pem"},
+		{"ca.pem", nil, "ca.pem"},
+	}
+
+	for i, tc := range cases {
+		data, file := pemOrFile(tc.Input)
+		if !reflect.DeepEqual(data, tc.Data) || file != tc.File {
+			t.Fatalf("%d: bad: %q, %q", i, data, file)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/validation"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+)
+
+var limitRangeItemResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"type": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"max": {
+			Type:         schema.TypeMap,
+			Optional:     true,
+			ValidateFunc: validateQuantityMap,
+			Elem: &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+		"min": {
+			Type:         schema.TypeMap,
+			Optional:     true,
+			ValidateFunc: validateQuantityMap,
+			Elem: &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+		"default": {
+			Type:         schema.TypeMap,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validateQuantityMap,
+			Elem: &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+		"default_request": {
+			Type:         schema.TypeMap,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validateQuantityMap,
+			Elem: &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+		"max_limit_request_ratio": {
+			Type:         schema.TypeMap,
+			Optional:     true,
+			ValidateFunc: validateQuantityMap,
+			Elem: &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+	},
+}
+
+func limitRangeResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "default",
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"annotations": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"limit": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem:     limitRangeItemResourceSpec,
+			},
+		},
+		Create: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace := r.Get("namespace").(string)
+			name := r.Get("name").(string)
+
+			item := &api.LimitRange{}
+			item.Name = name
+
+			err := writeLimitRange(r, item)
+			if err != nil {
+				return err
+			}
+
+			err = writeLastApplied(client.RESTClient, item)
+			if err != nil {
+				return err
+			}
+
+			item, err = client.LimitRanges(namespace).Create(item)
+			if err != nil {
+				return err
+			}
+
+			r.SetId(join(namespace, name))
+			return nil
+		},
+		Read: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			item, err := client.LimitRanges(namespace).Get(name)
+			if errors.IsNotFound(err) {
+				r.SetId("")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			readLabels(r, &item.ObjectMeta)
+			readAnnotations(r, &item.ObjectMeta)
+
+			// the server defaults default and default_request of
+			// container limits from max and min
+			previous, _ := r.Get("limit").([]interface{})
+			var limits []interface{}
+			for i, x := range item.Spec.Limits {
+				var p map[string]interface{}
+				if i < len(previous) {
+					p, _ = previous[i].(map[string]interface{})
+				}
+				limits = append(limits, map[string]interface{}{
+					"type":                    string(x.Type),
+					"max":                     readResourceList(x.Max, p["max"]),
+					"min":                     readResourceList(x.Min, p["min"]),
+					"default":                 readResourceList(x.Default, p["default"]),
+					"default_request":         readResourceList(x.DefaultRequest, p["default_request"]),
+					"max_limit_request_ratio": readResourceList(x.MaxLimitRequestRatio, p["max_limit_request_ratio"]),
+				})
+			}
+
+			r.Set("name", item.ObjectMeta.Name)
+			r.Set("limit", limits)
+			return nil
+		},
+		Update: func(r *schema.ResourceData, v interface{}) error {
+
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			return patchObject(client.RESTClient, "limitranges", r, v, func(r *schema.ResourceData) (runtime.Object, error) {
+				item := &api.LimitRange{}
+				item.Namespace = namespace
+				item.Name = name
+
+				err := writeLimitRange(r, item)
+				return item, err
+			}, &api.LimitRange{})
+		},
+		Delete: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			err = client.LimitRanges(namespace).Delete(name)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			return nil
+		},
+		Exists: func(r *schema.ResourceData, v interface{}) (bool, error) {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return false, err
+			}
+
+			_, err = client.LimitRanges(namespace).Get(name)
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			return true, nil
+		},
+	}
+}
+
+func writeLimitRange(r *schema.ResourceData, item *api.LimitRange) error {
+	writeLabels(r, &item.ObjectMeta)
+	writeAnnotations(r, &item.ObjectMeta)
+
+	item.Spec.Limits = nil
+	if l, _ := r.Get("limit").([]interface{}); l != nil {
+		for _, v := range l {
+			m := v.(map[string]interface{})
+			x := api.LimitRangeItem{}
+			x.Type = api.LimitType(m["type"].(string))
+
+			var err error
+			for _, f := range []struct {
+				key  string
+				list *api.ResourceList
+			}{
+				{"max", &x.Max},
+				{"min", &x.Min},
+				{"default", &x.Default},
+				{"default_request", &x.DefaultRequest},
+				{"max_limit_request_ratio", &x.MaxLimitRequestRatio},
+			} {
+				*f.list, err = writeResourceList(m[f.key])
+				if err != nil {
+					return err
+				}
+			}
+
+			item.Spec.Limits = append(item.Spec.Limits, x)
+		}
+	}
+
+	return nil
+}
+
+var limitRangeFieldMappings = []fieldMapping{
+	{"metadata", ""},
+	{"spec", ""},
+}
+
+func validateLimitRangeConfig(r *schema.ResourceData) field.ErrorList {
+	item := &api.LimitRange{}
+	item.Namespace = r.Get("namespace").(string)
+	item.Name = r.Get("name").(string)
+
+	err := writeLimitRange(r, item)
+	if err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "limits"), "", err.Error())}
+	}
+
+	return validation.ValidateLimitRange(item)
+}
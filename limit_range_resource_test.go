@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/resource"
+)
+
+// TestLimitRangeServerDefaults reads a limit range as the server stores it
+// and diffs it against the configuration it was created from.
+func TestLimitRangeServerDefaults(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+
+	// the server defaults default from max and default_request from
+	// default, the quantities are returned in canonical form
+	item := &api.LimitRange{}
+	item.Namespace = "default"
+	item.Name = "limits"
+	item.Spec.Limits = []api.LimitRangeItem{{
+		Type: api.LimitTypeContainer,
+		Max: api.ResourceList{
+			api.ResourceCPU:    resource.MustParse("0.5"),
+			api.ResourceMemory: resource.MustParse("1Gi"),
+		},
+		Min: api.ResourceList{
+			api.ResourceCPU: resource.MustParse("0.1"),
+		},
+		Default: api.ResourceList{
+			api.ResourceCPU:    resource.MustParse("0.5"),
+			api.ResourceMemory: resource.MustParse("1Gi"),
+		},
+		DefaultRequest: api.ResourceList{
+			api.ResourceCPU:    resource.MustParse("0.5"),
+			api.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}}
+	s.put("/api/v1/namespaces/default/limitranges/limits", item)
+
+	raw := map[string]interface{}{
+		"name": "limits",
+		"limit": []interface{}{
+			map[string]interface{}{
+				"type": "Container",
+				"max":  map[string]interface{}{"cpu": "0.5", "memory": "1024Mi"},
+				"min":  map[string]interface{}{"cpu": "0.1"},
+			},
+		},
+	}
+	rc, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	c := terraform.NewResourceConfig(rc)
+
+	res := limitRangeResource()
+	r := resourceData(t, res, "default/limits", map[string]string{
+		"namespace":          "default",
+		"name":               "limits",
+		"limit.#":            "1",
+		"limit.0.type":       "Container",
+		"limit.0.max.#":      "2",
+		"limit.0.max.cpu":    "0.5",
+		"limit.0.max.memory": "1024Mi",
+		"limit.0.min.#":      "1",
+		"limit.0.min.cpu":    "0.1",
+	})
+	err = res.Read(r, s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d, err := res.Diff(r.State(), c)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d != nil && !d.Empty() {
+		t.Fatalf("bad: %#v", d.Attributes)
+	}
+}
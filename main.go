@@ -1,6 +1,9 @@
 package main
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/plugin"
 	"github.com/hashicorp/terraform/terraform"
@@ -15,34 +18,129 @@ func main() {
 }
 
 func Provider() terraform.ResourceProvider {
-	return &schema.Provider{
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"host": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
 			},
 			"username": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
 			},
 			"password": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+			},
+			"token": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"insecure": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"client_certificate": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"client_key": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"cluster_ca_certificate": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"config_path": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"config_context": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"owner": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  defaultOwner,
+			},
+			"ignore_labels": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"ignore_annotations": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"kubernetes_namespace":              namespaceResource(),
-			"kubernetes_secret":                 secretsResource(),
-			"kubernetes_service":                serviceResource(),
-			"kubernetes_replication_controller": replicationControllerResource(),
+			"kubernetes_namespace":                 namespaceResource(),
+			"kubernetes_secret":                    secretsResource(),
+			"kubernetes_service":                   serviceResource(),
+			"kubernetes_replication_controller":    replicationControllerResource(),
+			"kubernetes_deployment":                deploymentResource(),
+			"kubernetes_config_map":                configMapResource(),
+			"kubernetes_persistent_volume":         persistentVolumeResource(),
+			"kubernetes_persistent_volume_claim":   persistentVolumeClaimResource(),
+			"kubernetes_daemon_set":                daemonSetResource(),
+			"kubernetes_job":                       jobResource(),
+			"kubernetes_ingress":                   ingressResource(),
+			"kubernetes_horizontal_pod_autoscaler": horizontalPodAutoscalerResource(),
+			"kubernetes_service_account":           serviceAccountResource(),
+			"kubernetes_resource_quota":            resourceQuotaResource(),
+			"kubernetes_limit_range":               limitRangeResource(),
+			"kubernetes_manifest":                  manifestResource(),
 		},
 		ConfigureFunc: func(r *schema.ResourceData) (interface{}, error) {
 
-			config := &client.Config{
-				Insecure: true,
-				Host:     "https://" + r.Get("host").(string),
-				Username: r.Get("username").(string),
-				Password: r.Get("password").(string),
+			config := &client.Config{}
+
+			if x, ok := r.GetOk("config_path"); ok {
+				err := loadKubeconfig(config, x.(string), r.Get("config_context").(string))
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if x, ok := r.GetOk("host"); ok {
+				config.Host = x.(string)
+			}
+			if config.Host == "" {
+				return nil, fmt.Errorf("either host or config_path must be set")
+			}
+			if !strings.Contains(config.Host, "://") {
+				config.Host = "https://" + config.Host
+			}
+
+			if x, ok := r.GetOk("username"); ok {
+				config.Username = x.(string)
+			}
+			if x, ok := r.GetOk("password"); ok {
+				config.Password = x.(string)
+			}
+			if x, ok := r.GetOk("token"); ok {
+				config.BearerToken = x.(string)
+			}
+			if x, ok := r.GetOk("insecure"); ok {
+				config.Insecure = x.(bool)
+			}
+
+			if x, ok := r.GetOk("client_certificate"); ok {
+				config.CertData, config.CertFile = pemOrFile(x.(string))
+			}
+			if x, ok := r.GetOk("client_key"); ok {
+				config.KeyData, config.KeyFile = pemOrFile(x.(string))
+			}
+			if x, ok := r.GetOk("cluster_ca_certificate"); ok {
+				config.CAData, config.CAFile = pemOrFile(x.(string))
 			}
 
 			client, err := client.New(config)
@@ -50,8 +148,67 @@ func Provider() terraform.ResourceProvider {
 				return nil, err
 			}
 
-			return client, nil
+			meta := &providerMeta{
+				client: client,
+				owner:  r.Get("owner").(string),
+			}
+			if l, _ := r.Get("ignore_labels").([]interface{}); l != nil {
+				for _, x := range l {
+					meta.ignoreLabels = append(meta.ignoreLabels, x.(string))
+				}
+			}
+			if l, _ := r.Get("ignore_annotations").([]interface{}); l != nil {
+				for _, x := range l {
+					meta.ignoreAnnotations = append(meta.ignoreAnnotations, x.(string))
+				}
+			}
+
+			return meta, nil
+
+		},
+	}
 
+	for _, res := range p.ResourcesMap {
+		trackPreviousState(res)
+		enforceOwnership(res)
+		ignoreMetadata(res)
+	}
+
+	return &provider{
+		Provider: p,
+		validators: map[string]validateFunc{
+			"kubernetes_namespace":                 validateNamespaceConfig,
+			"kubernetes_secret":                    validateSecretConfig,
+			"kubernetes_service":                   validateServiceConfig,
+			"kubernetes_replication_controller":    validateControllerConfig,
+			"kubernetes_deployment":                validateDeploymentConfig,
+			"kubernetes_config_map":                validateConfigMapConfig,
+			"kubernetes_persistent_volume":         validatePersistentVolumeConfig,
+			"kubernetes_persistent_volume_claim":   validatePersistentVolumeClaimConfig,
+			"kubernetes_daemon_set":                validateDaemonSetConfig,
+			"kubernetes_job":                       validateJobConfig,
+			"kubernetes_ingress":                   validateIngressConfig,
+			"kubernetes_horizontal_pod_autoscaler": validateHorizontalPodAutoscalerConfig,
+			"kubernetes_service_account":           validateServiceAccountConfig,
+			"kubernetes_resource_quota":            validateResourceQuotaConfig,
+			"kubernetes_limit_range":               validateLimitRangeConfig,
+		},
+		mappings: map[string][]fieldMapping{
+			"kubernetes_namespace":                 objectMetaMappings,
+			"kubernetes_secret":                    objectMetaMappings,
+			"kubernetes_service":                   serviceFieldMappings,
+			"kubernetes_replication_controller":    podTemplateFieldMappings,
+			"kubernetes_deployment":                podTemplateFieldMappings,
+			"kubernetes_config_map":                objectMetaMappings,
+			"kubernetes_persistent_volume":         persistentVolumeFieldMappings,
+			"kubernetes_persistent_volume_claim":   persistentVolumeClaimFieldMappings,
+			"kubernetes_daemon_set":                podTemplateFieldMappings,
+			"kubernetes_job":                       podTemplateFieldMappings,
+			"kubernetes_ingress":                   objectMetaMappings,
+			"kubernetes_horizontal_pod_autoscaler": objectMetaMappings,
+			"kubernetes_service_account":           objectMetaMappings,
+			"kubernetes_resource_quota":            resourceQuotaFieldMappings,
+			"kubernetes_limit_range":               limitRangeFieldMappings,
 		},
 	}
 }
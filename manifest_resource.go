@@ -0,0 +1,449 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/meta"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+)
+
+// The manifest resource manages objects of any kind, including the kinds the
+// provider doesn't know, from a YAML or JSON manifest. The state holds the
+// manifest as canonical JSON, a refresh only reads back the fields that are
+// set in the manifest.
+
+func manifestResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"manifest": {
+				Type:         schema.TypeString,
+				Required:     true,
+				StateFunc:    manifestStateFunc,
+				ValidateFunc: validateManifest,
+			},
+
+			"namespace": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		Create: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+
+			obj, err := parseManifest(r.Get("manifest").(string))
+			if err != nil {
+				return err
+			}
+			o, err := resolveManifest(client, obj)
+			if err != nil {
+				return err
+			}
+
+			r.Set("namespace", o.namespace)
+			r.Set("name", o.name)
+
+			writeManifestOwner(r, obj)
+			applied, err := lastAppliedConfiguration(obj)
+			if err != nil {
+				return err
+			}
+			setManifestAnnotation(obj, lastAppliedAnnotation, applied)
+
+			data, err := json.Marshal(obj)
+			if err != nil {
+				return err
+			}
+
+			err = client.DiscoveryClient.RESTClient.Post().
+				AbsPath(o.collection).
+				SetHeader("Content-Type", "application/json").
+				Body(data).
+				Do().
+				Error()
+			if err != nil {
+				return err
+			}
+
+			r.SetId(o.id())
+			return resourceManifestRead(r, v)
+		},
+		Read: resourceManifestRead,
+		Update: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+
+			// an adopted object has no previous manifest, the patch then
+			// only sets the fields of the manifest
+			original := make(map[string]interface{})
+			if x := extractPrevious(v).Get("manifest").(string); x != "" {
+				var err error
+				original, err = parseManifest(x)
+				if err != nil {
+					return err
+				}
+			}
+			modified, err := parseManifest(r.Get("manifest").(string))
+			if err != nil {
+				return err
+			}
+			writeManifestOwner(r, modified)
+
+			o, err := resolveManifest(client, modified)
+			if err != nil {
+				return err
+			}
+
+			current, err := getManifestObject(client, o)
+			if err != nil {
+				return err
+			}
+
+			// strategic merge patches only work for the kinds the server
+			// has types for, a JSON merge patch works for all of them
+			patch, err := threeWayPatch(original, modified, current, nil)
+			if err != nil {
+				return err
+			}
+			data, err := json.Marshal(patch)
+			if err != nil {
+				return err
+			}
+
+			err = client.DiscoveryClient.RESTClient.Patch(api.MergePatchType).
+				AbsPath(o.path()).
+				Body(data).
+				Do().
+				Error()
+			if err != nil {
+				return err
+			}
+
+			return resourceManifestRead(r, v)
+		},
+		Delete: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+
+			obj, err := parseManifest(r.Get("manifest").(string))
+			if err != nil {
+				return err
+			}
+			o, err := resolveManifest(client, obj)
+			if err != nil {
+				return err
+			}
+
+			err = client.DiscoveryClient.RESTClient.Delete().
+				AbsPath(o.path()).
+				Do().
+				Error()
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			return nil
+		},
+		Exists: func(r *schema.ResourceData, v interface{}) (bool, error) {
+			client := extractClient(v)
+
+			obj, err := parseManifest(r.Get("manifest").(string))
+			if err != nil {
+				return false, err
+			}
+			o, err := resolveManifest(client, obj)
+			if err != nil {
+				return false, err
+			}
+
+			_, err = getManifestObject(client, o)
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			return true, nil
+		},
+	}
+}
+
+func resourceManifestRead(r *schema.ResourceData, v interface{}) error {
+	client := extractClient(v)
+
+	obj, err := parseManifest(r.Get("manifest").(string))
+	if err != nil {
+		return err
+	}
+	o, err := resolveManifest(client, obj)
+	if err != nil {
+		return err
+	}
+
+	live, err := getManifestObject(client, o)
+	if errors.IsNotFound(err) {
+		r.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(projectManifest(obj, live))
+	if err != nil {
+		return err
+	}
+
+	annotations := make(map[string]string)
+	metadata, _ := live["metadata"].(map[string]interface{})
+	if m, _ := metadata["annotations"].(map[string]interface{}); m != nil {
+		for k, x := range m {
+			annotations[k], _ = x.(string)
+		}
+	}
+	readOwner(r, annotations)
+
+	r.Set("manifest", string(data))
+	r.Set("namespace", o.namespace)
+	r.Set("name", o.name)
+	return nil
+}
+
+// manifestObject locates the object of a manifest on the API server.
+type manifestObject struct {
+	// collection is the REST path of the resource the object belongs to
+	collection string
+	namespace  string
+	name       string
+}
+
+func (o *manifestObject) path() string {
+	return path.Join(o.collection, o.name)
+}
+
+func (o *manifestObject) id() string {
+	if o.namespace == "" {
+		return o.name
+	}
+	return join(o.namespace, o.name)
+}
+
+// resolveManifest finds the REST path of the object described by obj. The
+// kinds the client has types for are mapped with api.RESTMapper, other kinds
+// like third party resources are looked up with the discovery API.
+func resolveManifest(c *client.Client, obj map[string]interface{}) (*manifestObject, error) {
+	apiVersion, kind, namespace, name := manifestIdentity(obj)
+	if apiVersion == "" || kind == "" || name == "" {
+		return nil, fmt.Errorf("manifest must set apiVersion, kind and metadata.name")
+	}
+
+	gv, err := unversioned.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		resource   string
+		namespaced bool
+	)
+	mapping, err := api.RESTMapper.RESTMapping(gv.WithKind(kind).GroupKind(), gv.Version)
+	if err == nil {
+		resource = mapping.Resource
+		namespaced = mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	} else {
+		list, err := c.Discovery().ServerResourcesForGroupVersion(apiVersion)
+		if err != nil {
+			return nil, err
+		}
+		for _, res := range list.APIResources {
+			// subresources like pods/log share the kind of their parent
+			if res.Kind == kind && !strings.Contains(res.Name, "/") {
+				resource = res.Name
+				namespaced = res.Namespaced
+				break
+			}
+		}
+		if resource == "" {
+			return nil, fmt.Errorf("the server has no resource for kind %s in %s", kind, apiVersion)
+		}
+	}
+
+	prefix := "/apis/" + apiVersion
+	if gv.Group == "" {
+		prefix = "/api/" + apiVersion
+	}
+
+	o := &manifestObject{name: name}
+	if namespaced {
+		if namespace == "" {
+			namespace = "default"
+		}
+		o.namespace = namespace
+		o.collection = path.Join(prefix, "namespaces", namespace, resource)
+	} else {
+		o.collection = path.Join(prefix, resource)
+	}
+	return o, nil
+}
+
+func getManifestObject(c *client.Client, o *manifestObject) (map[string]interface{}, error) {
+	data, err := c.DiscoveryClient.RESTClient.Get().
+		AbsPath(o.path()).
+		Do().
+		Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]interface{}
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func manifestIdentity(obj map[string]interface{}) (apiVersion, kind, namespace, name string) {
+	apiVersion, _ = obj["apiVersion"].(string)
+	kind, _ = obj["kind"].(string)
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	namespace, _ = metadata["namespace"].(string)
+	name, _ = metadata["name"].(string)
+	return
+}
+
+// parseManifest decodes a YAML or JSON manifest, JSON is a subset of YAML.
+func parseManifest(s string) (map[string]interface{}, error) {
+	data, err := yaml.YAMLToJSON([]byte(s))
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]interface{}
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, fmt.Errorf("manifest is empty")
+	}
+	return m, nil
+}
+
+// manifestStateFunc stores the manifest as JSON with sorted keys, so that
+// formatting changes are not a diff.
+func manifestStateFunc(v interface{}) string {
+	s, _ := v.(string)
+	m, err := parseManifest(s)
+	if err != nil {
+		return s
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return s
+	}
+	return string(data)
+}
+
+func validateManifest(v interface{}, _ string) ([]string, []error) {
+	m, err := parseManifest(v.(string))
+	if err != nil {
+		return nil, []error{err}
+	}
+	apiVersion, kind, _, name := manifestIdentity(m)
+	if apiVersion == "" || kind == "" || name == "" {
+		return nil, []error{fmt.Errorf("manifest must set apiVersion, kind and metadata.name")}
+	}
+	return nil, nil
+}
+
+// projectManifest returns the fields of live that are set in manifest, the
+// fields that the server or controllers add are not drift. Lists keep the
+// length they have in live.
+func projectManifest(manifest, live interface{}) interface{} {
+	switch m := manifest.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		p := make(map[string]interface{}, len(m))
+		for k, x := range m {
+			if y, ok := l[k]; ok {
+				p[k] = projectManifest(x, y)
+			}
+		}
+		return p
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			return live
+		}
+		p := make([]interface{}, len(l))
+		for i, y := range l {
+			if i < len(m) {
+				p[i] = projectManifest(m[i], y)
+			} else {
+				p[i] = y
+			}
+		}
+		return p
+	}
+	return live
+}
+
+func writeManifestOwner(r *schema.ResourceData, obj map[string]interface{}) {
+	annotations := make(map[string]string)
+	writeOwner(r, annotations)
+	for k, x := range annotations {
+		setManifestAnnotation(obj, k, x)
+	}
+}
+
+func setManifestAnnotation(obj map[string]interface{}, key, value string) {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+		obj["metadata"] = metadata
+	}
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	if annotations == nil {
+		annotations = make(map[string]interface{})
+		metadata["annotations"] = annotations
+	}
+	annotations[key] = value
+}
+
+// requireNewManifest replaces the object when the manifest describes another
+// object, a patch can't rename or move it.
+func requireNewManifest(s *terraform.InstanceState, d *terraform.InstanceDiff) {
+	a, ok := d.Attributes["manifest"]
+	if !ok || s == nil || s.ID == "" {
+		return
+	}
+
+	before, err := parseManifest(a.Old)
+	if err != nil {
+		return
+	}
+	after, err := parseManifest(a.New)
+	if err != nil {
+		return
+	}
+
+	oldVersion, oldKind, oldNamespace, oldName := manifestIdentity(before)
+	newVersion, newKind, newNamespace, newName := manifestIdentity(after)
+	if oldVersion != newVersion || oldKind != newKind || oldNamespace != newNamespace || oldName != newName {
+		a.RequiresNew = true
+	}
+}
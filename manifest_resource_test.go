@@ -0,0 +1,215 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestProjectManifest(t *testing.T) {
+	cases := []struct {
+		Manifest interface{}
+		Live     interface{}
+		Expected interface{}
+	}{
+		// fields added by the server are left out
+		{
+			Manifest: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "web"},
+				"spec":     map[string]interface{}{"replicas": 1.0},
+			},
+			Live: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "web", "uid": "1234"},
+				"spec":     map[string]interface{}{"replicas": 3.0, "paused": false},
+				"status":   map[string]interface{}{"replicas": 3.0},
+			},
+			Expected: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "web"},
+				"spec":     map[string]interface{}{"replicas": 3.0},
+			},
+		},
+		// fields removed from the object are left out
+		{
+			Manifest: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "web", "labels": map[string]interface{}{"app": "web"}},
+			},
+			Live: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "web"},
+			},
+			Expected: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "web"},
+			},
+		},
+		// lists keep the length of the object
+		{
+			Manifest: []interface{}{
+				map[string]interface{}{"name": "web"},
+			},
+			Live: []interface{}{
+				map[string]interface{}{"name": "web", "imagePullPolicy": "Always"},
+				map[string]interface{}{"name": "log"},
+			},
+			Expected: []interface{}{
+				map[string]interface{}{"name": "web"},
+				map[string]interface{}{"name": "log"},
+			},
+		},
+		{
+			Manifest: []interface{}{"a", "b"},
+			Live:     []interface{}{"a"},
+			Expected: []interface{}{"a"},
+		},
+		// changed types are taken from the object
+		{
+			Manifest: map[string]interface{}{"port": map[string]interface{}{"name": "http"}},
+			Live:     map[string]interface{}{"port": 80.0},
+			Expected: map[string]interface{}{"port": 80.0},
+		},
+		{
+			Manifest: "web",
+			Live:     "api",
+			Expected: "api",
+		},
+	}
+
+	for i, tc := range cases {
+		actual := projectManifest(tc.Manifest, tc.Live)
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestManifestStateFunc(t *testing.T) {
+	cases := []struct {
+		Input    string
+		Expected string
+	}{
+		{
+			Input:    "kind: ConfigMap\napiVersion: v1\nmetadata:\n  name: web\n",
+			Expected: `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"web"}}`,
+		},
+		{
+			Input:    `{"metadata": {"name": "web"}, "kind": "ConfigMap"}`,
+			Expected: `{"kind":"ConfigMap","metadata":{"name":"web"}}`,
+		},
+		// invalid manifests are stored as they are, validation reports them
+		{
+			Input:    "kind: [",
+			Expected: "kind: [",
+		},
+		{
+			Input:    "",
+			Expected: "",
+		},
+	}
+
+	for i, tc := range cases {
+		actual := manifestStateFunc(tc.Input)
+		if actual != tc.Expected {
+			t.Fatalf("%d: bad: %s", i, actual)
+		}
+	}
+}
+
+func TestValidateManifest(t *testing.T) {
+	cases := []struct {
+		Input string
+		Err   bool
+	}{
+		{"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\n", false},
+		{"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  generateName: web-\n", true},
+		{"kind: ConfigMap\nmetadata:\n  name: web\n", true},
+		{"", true},
+		{"kind: [", true},
+	}
+
+	for i, tc := range cases {
+		_, errs := validateManifest(tc.Input, "manifest")
+		if (len(errs) > 0) != tc.Err {
+			t.Fatalf("%d: errs: %v", i, errs)
+		}
+	}
+}
+
+func TestRequireNewManifest(t *testing.T) {
+	web := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"web","namespace":"default"}}`
+
+	cases := []struct {
+		ID       string
+		Old      string
+		New      string
+		Expected bool
+	}{
+		{"default/web", web, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"web","namespace":"default","labels":{"app":"web"}}}`, false},
+		{"default/web", web, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"api","namespace":"default"}}`, true},
+		{"default/web", web, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"web","namespace":"kube-system"}}`, true},
+		{"default/web", web, `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"web","namespace":"default"}}`, true},
+		{"default/web", web, `{"apiVersion":"v2","kind":"ConfigMap","metadata":{"name":"web","namespace":"default"}}`, true},
+		// new objects and unparsable manifests are left to the diff
+		{"", web, `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"web"}}`, false},
+		{"default/web", web, "kind: [", false},
+	}
+
+	for i, tc := range cases {
+		s := &terraform.InstanceState{ID: tc.ID}
+		d := &terraform.InstanceDiff{
+			Attributes: map[string]*terraform.ResourceAttrDiff{
+				"manifest": {Old: tc.Old, New: tc.New},
+			},
+		}
+		requireNewManifest(s, d)
+		if d.Attributes["manifest"].RequiresNew != tc.Expected {
+			t.Fatalf("%d: bad: %v", i, d.Attributes["manifest"].RequiresNew)
+		}
+	}
+}
+
+// TestManifestApply creates and updates an object from a manifest on a
+// server that lower cases the mode of widgets. The state holds the object as
+// the server stored it.
+func TestManifestApply(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+	s.react = func(s *fakeServer, p string, obj map[string]interface{}) {
+		if spec, ok := obj["spec"].(map[string]interface{}); ok {
+			mode, _ := spec["mode"].(string)
+			spec["mode"] = strings.ToLower(mode)
+		}
+	}
+
+	res := providerResource(manifestResource())
+	manifest := func(mode string) map[string]interface{} {
+		return map[string]interface{}{
+			"manifest": `{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"web"},"spec":{"mode":"` + mode + `"}}`,
+		}
+	}
+
+	state, err := apply(t, res, nil, manifest("Fast"), s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]string{
+		"id":        "default/web",
+		"namespace": "default",
+		"name":      "web",
+		"owner":     defaultOwner,
+		"manifest":  `{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"web"},"spec":{"mode":"fast"}}`,
+	}
+	for k, x := range expected {
+		if state.Attributes[k] != x {
+			t.Fatalf("bad %s: %s", k, state.Attributes[k])
+		}
+	}
+
+	state, err = apply(t, res, state, manifest("Slow"), s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected["manifest"] = `{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"web"},"spec":{"mode":"slow"}}`
+	if x := state.Attributes["manifest"]; x != expected["manifest"] {
+		t.Fatalf("bad manifest: %s", x)
+	}
+}
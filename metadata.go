@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+
+	"k8s.io/kubernetes/pkg/api"
+)
+
+// ignoreMetadata wraps the Read function of res so that labels and
+// annotations matching the ignored prefixes of the provider keep the value
+// they have in the state. The server or other tools own these keys, a
+// change to them is not drift.
+func ignoreMetadata(res *schema.Resource) {
+	if _, ok := res.Schema["labels"]; !ok {
+		return
+	}
+
+	read := res.Read
+	res.Read = func(r *schema.ResourceData, v interface{}) error {
+		labels := r.Get("labels")
+		annotations := r.Get("annotations")
+
+		err := read(r, v)
+		if err != nil || r.Id() == "" {
+			return err
+		}
+
+		m := v.(*providerMeta)
+		r.Set("labels", keepIgnoredKeys(r.Get("labels"), labels, m.ignoreLabels))
+		r.Set("annotations", keepIgnoredKeys(r.Get("annotations"), annotations, m.ignoreAnnotations))
+		return nil
+	}
+}
+
+func keepIgnoredKeys(current, previous interface{}, prefixes []string) map[string]interface{} {
+	m := make(map[string]interface{})
+	if x, _ := current.(map[string]interface{}); x != nil {
+		for k, v := range x {
+			if !hasAnyPrefix(k, prefixes) {
+				m[k] = v
+			}
+		}
+	}
+	if x, _ := previous.(map[string]interface{}); x != nil {
+		for k, v := range x {
+			if hasAnyPrefix(k, prefixes) {
+				m[k] = v
+			}
+		}
+	}
+	return m
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// keepForeignMetadata adds the labels and annotations that other tools set on
+// live to meta, for objects that are replaced instead of patched. The keys of
+// the last applied configuration of live were set by Terraform, they are
+// only kept when meta still has them.
+func keepForeignMetadata(meta, live *api.ObjectMeta) {
+	var applied struct {
+		Metadata struct {
+			Labels      map[string]string `json:"labels"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}
+	if x := live.Annotations[lastAppliedAnnotation]; x != "" {
+		json.Unmarshal([]byte(x), &applied)
+	}
+
+	meta.Labels = keepForeignKeys(meta.Labels, live.Labels, applied.Metadata.Labels)
+	meta.Annotations = keepForeignKeys(meta.Annotations, live.Annotations, applied.Metadata.Annotations)
+}
+
+func keepForeignKeys(m, live, applied map[string]string) map[string]string {
+	for k, v := range live {
+		if _, ok := m[k]; ok {
+			continue
+		}
+		if _, ok := applied[k]; ok {
+			continue
+		}
+		if m == nil {
+			m = make(map[string]string)
+		}
+		m[k] = v
+	}
+	return m
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeepIgnoredKeys(t *testing.T) {
+	prefixes := []string{"kubernetes.io/", "deployment."}
+
+	cases := []struct {
+		Current  interface{}
+		Previous interface{}
+		Expected map[string]interface{}
+	}{
+		// keys without an ignored prefix come from the object
+		{
+			Current:  map[string]interface{}{"app": "web", "tier": "front"},
+			Previous: map[string]interface{}{"app": "api"},
+			Expected: map[string]interface{}{"app": "web", "tier": "front"},
+		},
+		// ignored keys keep the value of the state
+		{
+			Current:  map[string]interface{}{"app": "web", "kubernetes.io/created-by": "new"},
+			Previous: map[string]interface{}{"app": "web", "kubernetes.io/created-by": "old"},
+			Expected: map[string]interface{}{"app": "web", "kubernetes.io/created-by": "old"},
+		},
+		// ignored keys added to the object don't show up
+		{
+			Current:  map[string]interface{}{"app": "web", "deployment.kubernetes.io/revision": "2"},
+			Previous: map[string]interface{}{"app": "web"},
+			Expected: map[string]interface{}{"app": "web"},
+		},
+		// ignored keys removed from the object stay in the state
+		{
+			Current:  map[string]interface{}{},
+			Previous: map[string]interface{}{"deployment.kubernetes.io/revision": "1"},
+			Expected: map[string]interface{}{"deployment.kubernetes.io/revision": "1"},
+		},
+		{
+			Current:  nil,
+			Previous: nil,
+			Expected: map[string]interface{}{},
+		},
+	}
+
+	for i, tc := range cases {
+		actual := keepIgnoredKeys(tc.Current, tc.Previous, prefixes)
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestHasAnyPrefix(t *testing.T) {
+	cases := []struct {
+		Input    string
+		Prefixes []string
+		Expected bool
+	}{
+		{"kubernetes.io/created-by", []string{"kubernetes.io/"}, true},
+		{"deployment.kubernetes.io/revision", []string{"kubernetes.io/"}, false},
+		{"deployment.kubernetes.io/revision", []string{"app", "deployment."}, true},
+		{"app", nil, false},
+		{"app", []string{""}, true},
+	}
+
+	for i, tc := range cases {
+		actual := hasAnyPrefix(tc.Input, tc.Prefixes)
+		if actual != tc.Expected {
+			t.Fatalf("%d: bad: %v", i, actual)
+		}
+	}
+}
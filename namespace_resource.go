@@ -1,10 +1,19 @@
 package main
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/hashicorp/terraform/helper/schema"
 
 	"k8s.io/kubernetes/pkg/api"
 	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/validation"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+	"k8s.io/kubernetes/pkg/util/wait"
 )
 
 func namespaceResource() *schema.Resource {
@@ -31,6 +40,12 @@ func namespaceResource() *schema.Resource {
 					Required: true,
 				},
 			},
+			"delete_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "5m",
+				ValidateFunc: validateDuration,
+			},
 		},
 		Create: func(r *schema.ResourceData, v interface{}) error {
 			client := extractClient(v)
@@ -42,7 +57,12 @@ func namespaceResource() *schema.Resource {
 			writeLabels(r, &item.ObjectMeta)
 			writeAnnotations(r, &item.ObjectMeta)
 
-			item, err := client.Namespaces().Create(item)
+			err := writeLastApplied(client.RESTClient, item)
+			if err != nil {
+				return err
+			}
+
+			item, err = client.Namespaces().Create(item)
 			if err != nil {
 				return err
 			}
@@ -55,6 +75,10 @@ func namespaceResource() *schema.Resource {
 			name := r.Id()
 
 			item, err := client.Namespaces().Get(name)
+			if errors.IsNotFound(err) {
+				r.SetId("")
+				return nil
+			}
 			if err != nil {
 				return err
 			}
@@ -74,26 +98,33 @@ func namespaceResource() *schema.Resource {
 			client := extractClient(v)
 			name := r.Id()
 
-			item, err := client.Namespaces().Get(name)
+			return patchObject(client.RESTClient, "namespaces", r, v, func(r *schema.ResourceData) (runtime.Object, error) {
+				item := &api.Namespace{}
+				item.Name = name
+
+				writeLabels(r, &item.ObjectMeta)
+				writeAnnotations(r, &item.ObjectMeta)
+				return item, nil
+			}, &api.Namespace{})
+		},
+		Delete: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			name := r.Id()
+
+			timeout, err := readDuration(r, "delete_timeout", 5*time.Minute)
 			if err != nil {
 				return err
 			}
 
-			writeLabels(r, &item.ObjectMeta)
-			writeAnnotations(r, &item.ObjectMeta)
-
-			_, err = client.Namespaces().Update(item)
+			err = client.Namespaces().Delete(name)
+			if errors.IsNotFound(err) {
+				return nil
+			}
 			if err != nil {
 				return err
 			}
 
-			return nil
-		},
-		Delete: func(r *schema.ResourceData, v interface{}) error {
-			client := extractClient(v)
-			name := r.Id()
-
-			return client.Namespaces().Delete(name)
+			return waitForNamespaceDeletion(client, name, timeout)
 		},
 		Exists: func(r *schema.ResourceData, v interface{}) (bool, error) {
 			client := extractClient(v)
@@ -110,3 +141,109 @@ func namespaceResource() *schema.Resource {
 		},
 	}
 }
+
+// waitForNamespaceDeletion waits until a terminating namespace is gone. When
+// it times out the finalizers and the resources still holding up the
+// namespace are reported.
+func waitForNamespaceDeletion(c client.Interface, name string, timeout time.Duration) error {
+	var item *api.Namespace
+	err := wait.Poll(1*time.Second, timeout, func() (bool, error) {
+		var err error
+		item, err = c.Namespaces().Get(name)
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("namespace %q was not deleted within %s: %s",
+			name, timeout, describeNamespaceTermination(c, item))
+	}
+	return err
+}
+
+func describeNamespaceTermination(c client.Interface, item *api.Namespace) string {
+	if item == nil {
+		return "still terminating"
+	}
+
+	var reasons []string
+
+	if len(item.Spec.Finalizers) > 0 {
+		var finalizers []string
+		for _, f := range item.Spec.Finalizers {
+			finalizers = append(finalizers, string(f))
+		}
+		reasons = append(reasons, "finalizers "+strings.Join(finalizers, ", "))
+	}
+
+	namespace := item.Name
+	remaining := []struct {
+		kind string
+		list func() (int, error)
+	}{
+		{"pods", func() (int, error) {
+			l, err := c.Pods(namespace).List(api.ListOptions{})
+			if err != nil {
+				return 0, err
+			}
+			return len(l.Items), nil
+		}},
+		{"replication controllers", func() (int, error) {
+			l, err := c.ReplicationControllers(namespace).List(api.ListOptions{})
+			if err != nil {
+				return 0, err
+			}
+			return len(l.Items), nil
+		}},
+		{"services", func() (int, error) {
+			l, err := c.Services(namespace).List(api.ListOptions{})
+			if err != nil {
+				return 0, err
+			}
+			return len(l.Items), nil
+		}},
+		{"secrets", func() (int, error) {
+			l, err := c.Secrets(namespace).List(api.ListOptions{})
+			if err != nil {
+				return 0, err
+			}
+			return len(l.Items), nil
+		}},
+		{"persistent volume claims", func() (int, error) {
+			l, err := c.PersistentVolumeClaims(namespace).List(api.ListOptions{})
+			if err != nil {
+				return 0, err
+			}
+			return len(l.Items), nil
+		}},
+	}
+	for _, x := range remaining {
+		n, err := x.list()
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("failed to list %s (%s)", x.kind, err))
+		} else if n > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d %s", n, x.kind))
+		}
+	}
+
+	if len(reasons) == 0 {
+		return "still terminating"
+	}
+	return "held up by " + strings.Join(reasons, ", ")
+}
+
+func validateNamespaceConfig(r *schema.ResourceData) field.ErrorList {
+	item := &api.Namespace{}
+	item.Name = r.Get("name").(string)
+
+	writeLabels(r, &item.ObjectMeta)
+	writeAnnotations(r, &item.ObjectMeta)
+
+	err := applyDefaults(item, "v1")
+	if err != nil {
+		return field.ErrorList{field.InternalError(field.NewPath("metadata"), err)}
+	}
+
+	return validation.ValidateNamespace(item)
+}
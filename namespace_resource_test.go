@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/kubernetes/pkg/api"
+)
+
+func TestNamespaceDelete(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+
+	ns := &api.Namespace{}
+	ns.Name = "web"
+	s.put("/api/v1/namespaces/web", ns)
+
+	// states written before delete_timeout existed don't have it
+	res := namespaceResource()
+	r := resourceData(t, res, "web", map[string]string{"name": "web"})
+	err := res.Delete(r, s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if s.get("/api/v1/namespaces/web", &api.Namespace{}) {
+		t.Fatalf("namespace was not deleted")
+	}
+
+	// a namespace that is already gone is deleted
+	err = res.Delete(r, s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestWaitForNamespaceDeletion(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+
+	ns := &api.Namespace{}
+	ns.Name = "web"
+	ns.Spec.Finalizers = []api.FinalizerName{api.FinalizerKubernetes}
+	ns.Status.Phase = api.NamespaceTerminating
+	s.put("/api/v1/namespaces/web", ns)
+
+	for _, name := range []string{"a", "b"} {
+		pod := &api.Pod{}
+		pod.Name = name
+		s.put("/api/v1/namespaces/web/pods/"+name, pod)
+	}
+	claim := &api.PersistentVolumeClaim{}
+	claim.Name = "data"
+	s.put("/api/v1/namespaces/web/persistentvolumeclaims/data", claim)
+
+	err := waitForNamespaceDeletion(s.meta().client, "web", 1500*time.Millisecond)
+	if err == nil {
+		t.Fatalf("expected a timeout")
+	}
+	expected := `namespace "web" was not deleted within 1.5s: held up by finalizers kubernetes, 2 pods, 1 persistent volume claims`
+	if err.Error() != expected {
+		t.Fatalf("bad: %s", err)
+	}
+
+	s.delete("/api/v1/namespaces/web")
+	err = waitForNamespaceDeletion(s.meta().client, "web", 1500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestDescribeNamespaceTermination(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+
+	if x := describeNamespaceTermination(s.meta().client, nil); x != "still terminating" {
+		t.Fatalf("bad: %s", x)
+	}
+
+	ns := &api.Namespace{}
+	ns.Name = "web"
+	x := describeNamespaceTermination(s.meta().client, ns)
+	if x != "still terminating" {
+		t.Fatalf("bad: %s", x)
+	}
+
+	svc := &api.Service{}
+	svc.Name = "web"
+	s.put("/api/v1/namespaces/web/services/web", svc)
+	x = describeNamespaceTermination(s.meta().client, ns)
+	if !strings.HasSuffix(x, "held up by 1 services") {
+		t.Fatalf("bad: %s", x)
+	}
+}
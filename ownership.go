@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+
+	"k8s.io/kubernetes/pkg/api/errors"
+)
+
+// ownedAnnotation marks the objects managed by Terraform, its value is the
+// owner configured on the provider.
+const ownedAnnotation = "terraform.io/owned"
+
+// defaultOwner is the owner of objects when the provider doesn't configure
+// one, it is also the value older versions of the provider wrote.
+const defaultOwner = "true"
+
+// enforceOwnership adds the ownership attributes to res and wraps its
+// Create, Update and Delete functions. Objects that are not marked as owned
+// by this provider are only changed when adopt_existing or force_ownership
+// allow it.
+func enforceOwnership(res *schema.Resource) {
+	res.Schema["owner"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+	res.Schema["adopt_existing"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+	}
+	res.Schema["force_ownership"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+	}
+
+	create := res.Create
+	update := res.Update
+	del := res.Delete
+
+	res.Create = func(r *schema.ResourceData, v interface{}) error {
+		owner := extractOwner(v)
+		r.Set("owner", owner)
+
+		err := create(r, v)
+		if !errors.IsAlreadyExists(err) || !r.Get("adopt_existing").(bool) {
+			return err
+		}
+
+		// take over the existing object by updating it to the configuration
+		id := r.Get("name").(string)
+		if _, ok := res.Schema["namespace"]; ok {
+			if namespace := r.Get("namespace").(string); namespace != "" {
+				id = join(namespace, id)
+			}
+		}
+		r.SetId(id)
+
+		s, refreshErr := res.Refresh(r.State(), v)
+		if refreshErr != nil || s == nil {
+			r.SetId("")
+			if refreshErr != nil {
+				return refreshErr
+			}
+			return err
+		}
+
+		current := s.Attributes["owner"]
+		if current != "" && current != owner && !r.Get("force_ownership").(bool) {
+			r.SetId("")
+			return fmt.Errorf("%s is owned by %q, set force_ownership to adopt it anyway", id, current)
+		}
+
+		// the patch starts from the refreshed object, r only holds the
+		// configuration
+		v, err = withPrevious(res.Schema, s, v)
+		if err != nil {
+			r.SetId("")
+			return err
+		}
+
+		r.Set("owner", owner)
+		return update(r, v)
+	}
+
+	res.Update = func(r *schema.ResourceData, v interface{}) error {
+		err := checkOwnership(r, v, "update")
+		if err != nil {
+			return err
+		}
+
+		r.Set("owner", extractOwner(v))
+		return update(r, v)
+	}
+
+	res.Delete = func(r *schema.ResourceData, v interface{}) error {
+		err := checkOwnership(r, v, "delete")
+		if err != nil {
+			return err
+		}
+
+		return del(r, v)
+	}
+}
+
+// checkOwnership refuses to touch an object that, as of the last refresh,
+// is not marked as owned by this provider.
+func checkOwnership(r *schema.ResourceData, v interface{}, action string) error {
+	if r.Get("force_ownership").(bool) {
+		return nil
+	}
+
+	owner := extractOwner(v)
+	current, _ := r.Get("owner").(string)
+	if current == owner {
+		return nil
+	}
+	if current == "" {
+		return fmt.Errorf("refusing to %s %s, it is not marked as owned by terraform; set force_ownership to %s it anyway", action, r.Id(), action)
+	}
+	return fmt.Errorf("refusing to %s %s, it is owned by %q; set force_ownership to %s it anyway", action, r.Id(), current, action)
+}
+
+// readOwner sets the owner attribute from the object's annotations.
+func readOwner(r *schema.ResourceData, annotations map[string]string) {
+	r.Set("owner", annotations[ownedAnnotation])
+}
+
+// writeOwner marks the object as owned by the owner attribute, which is set
+// by the ownership wrappers before a Create or Update. The previous state of
+// an adopted object has no owner, so the patch adds the mark.
+func writeOwner(r *schema.ResourceData, annotations map[string]string) {
+	owner, _ := r.Get("owner").(string)
+	if owner == "" {
+		return
+	}
+	annotations[ownedAnnotation] = owner
+}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/runtime"
+)
+
+// lastAppliedAnnotation holds the configuration of the last apply in the
+// format kubectl apply uses, so both tools agree on which fields they set.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// buildFunc builds the Kubernetes object described by r.
+type buildFunc func(r *schema.ResourceData) (runtime.Object, error)
+
+// trackPreviousState wraps the Update function of res so that it can build
+// the object of the previous state, see extractPrevious.
+func trackPreviousState(res *schema.Resource) {
+	update := res.Update
+	res.Update = func(r *schema.ResourceData, v interface{}) error {
+		// adopting an object in Create already knows its previous state
+		if extractPrevious(v) != nil {
+			return update(r, v)
+		}
+
+		previous, err := previousData(res.Schema, r)
+		if err != nil {
+			return err
+		}
+
+		m := *v.(*providerMeta)
+		m.previous = previous
+		return update(r, &m)
+	}
+}
+
+// withPrevious returns a copy of the meta v that holds the values of state
+// as the previous state, see extractPrevious.
+func withPrevious(s map[string]*schema.Schema, state *terraform.InstanceState, v interface{}) (interface{}, error) {
+	var previous *schema.ResourceData
+
+	// refreshing hands Read a ResourceData for the state
+	tmp := &schema.Resource{
+		Schema: s,
+		Read: func(d *schema.ResourceData, _ interface{}) error {
+			previous = d
+			return nil
+		},
+	}
+	_, err := tmp.Refresh(state, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	m := *v.(*providerMeta)
+	m.previous = previous
+	return &m, nil
+}
+
+// previousData returns a copy of r that holds the values of the state before
+// the current change.
+func previousData(s map[string]*schema.Schema, r *schema.ResourceData) (*schema.ResourceData, error) {
+	var previous *schema.ResourceData
+
+	// applying an empty diff to an empty state hands a blank ResourceData
+	// to Create
+	tmp := &schema.Resource{
+		Schema: s,
+		Create: func(d *schema.ResourceData, _ interface{}) error {
+			for k := range s {
+				o, _ := r.GetChange(k)
+				err := d.Set(k, o)
+				if err != nil {
+					return err
+				}
+			}
+			previous = d
+			return nil
+		},
+	}
+	_, err := tmp.Apply(&terraform.InstanceState{}, &terraform.InstanceDiff{}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return previous, nil
+}
+
+// patchObject updates an object with a strategic merge patch of the changes
+// between the object built from the previous state and the one built from
+// the configuration. Fields set by the server, controllers or kubectl are not
+// part of the patch and keep their values, except for the fields of the last
+// applied configuration that the configuration no longer has. The patched
+// object is stored in into.
+func patchObject(
+	c *unversioned.RESTClient,
+	resource string,
+	r *schema.ResourceData,
+	v interface{},
+	build buildFunc,
+	into runtime.Object,
+) error {
+	original, err := build(extractPrevious(v))
+	if err != nil {
+		return err
+	}
+	modified, err := build(r)
+	if err != nil {
+		return err
+	}
+
+	meta, err := api.ObjectMetaFor(modified)
+	if err != nil {
+		return err
+	}
+
+	current, err := c.Get().
+		NamespaceIfScoped(meta.Namespace, meta.Namespace != "").
+		Resource(resource).
+		Name(meta.Name).
+		Do().
+		Get()
+	if err != nil {
+		return err
+	}
+
+	patch, err := createMergePatch(original, modified, current, c.APIVersion().String())
+	if err != nil {
+		return err
+	}
+
+	return c.Patch(api.StrategicMergePatchType).
+		NamespaceIfScoped(meta.Namespace, meta.Namespace != "").
+		Resource(resource).
+		Name(meta.Name).
+		Body(patch).
+		Do().
+		Into(into)
+}
+
+// createMergePatch returns the strategic merge patch that turns original into
+// modified, all objects are encoded in the given API version.
+func createMergePatch(original, modified, current runtime.Object, version string) ([]byte, error) {
+	o, _, err := versionedMap(original, version)
+	if err != nil {
+		return nil, err
+	}
+	m, t, err := versionedMap(modified, version)
+	if err != nil {
+		return nil, err
+	}
+	c, _, err := versionedMap(current, version)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := threeWayPatch(o, m, c, t)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(patch)
+}
+
+// threeWayPatch returns the patch that turns original into modified. Like
+// kubectl apply, fields of the last applied configuration of current that
+// modified doesn't have are removed, and modified becomes the new last
+// applied configuration.
+func threeWayPatch(original, modified, current map[string]interface{}, t reflect.Type) (map[string]interface{}, error) {
+	patch := diffMaps(original, modified, t)
+
+	meta, _ := current["metadata"].(map[string]interface{})
+	annotations, _ := meta["annotations"].(map[string]interface{})
+	last, _ := annotations[lastAppliedAnnotation].(string)
+	if last != "" {
+		var l map[string]interface{}
+		err := json.Unmarshal([]byte(last), &l)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %s", lastAppliedAnnotation, err)
+		}
+		mergePatches(patch, removedFields(l, modified, current, t))
+	}
+
+	applied, err := lastAppliedConfiguration(modified)
+	if err != nil {
+		return nil, err
+	}
+	if last != applied {
+		mergePatches(patch, map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]interface{}{
+					lastAppliedAnnotation: applied,
+				},
+			},
+		})
+	}
+
+	return patch, nil
+}
+
+func versionedMap(obj runtime.Object, version string) (map[string]interface{}, reflect.Type, error) {
+	ext, err := api.Scheme.ConvertToVersion(obj, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := json.Marshal(ext)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var m map[string]interface{}
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return m, reflect.TypeOf(ext), nil
+}
+
+// writeLastApplied sets the last applied configuration annotation of obj
+// before it is created.
+func writeLastApplied(c *unversioned.RESTClient, obj runtime.Object) error {
+	m, _, err := versionedMap(obj, c.APIVersion().String())
+	if err != nil {
+		return err
+	}
+	applied, err := lastAppliedConfiguration(m)
+	if err != nil {
+		return err
+	}
+
+	meta, err := api.ObjectMetaFor(obj)
+	if err != nil {
+		return err
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = make(map[string]string)
+	}
+	meta.Annotations[lastAppliedAnnotation] = applied
+	return nil
+}
+
+// lastAppliedConfiguration returns the value of the last applied
+// configuration annotation for the versioned object m. The status is left
+// out and so are the values of secrets, they are replaced by their digests
+// as in the state.
+func lastAppliedConfiguration(m map[string]interface{}) (string, error) {
+	applied := make(map[string]interface{}, len(m))
+	for k, x := range m {
+		if k != "status" {
+			applied[k] = x
+		}
+	}
+
+	if m["apiVersion"] == "v1" && m["kind"] == "Secret" {
+		data, _ := m["data"].(map[string]interface{})
+		digests := make(map[string]interface{}, len(data))
+		for k, x := range data {
+			v, _ := x.(string)
+			b, _ := base64.StdEncoding.DecodeString(v)
+			digests[k] = secretDigest(b)
+		}
+		applied["data"] = digests
+	}
+
+	b, err := json.Marshal(applied)
+	return string(b), err
+}
+
+// diffMaps returns the patch that turns original into modified. Removed keys
+// are set to null, maps and structs are patched recursively. Lists are
+// replaced, lists with the merge strategy get a replace directive as the
+// server would merge them with the list it has otherwise.
+func diffMaps(original, modified map[string]interface{}, t reflect.Type) map[string]interface{} {
+	patch := make(map[string]interface{})
+
+	for k, o := range original {
+		if _, ok := modified[k]; !ok {
+			patch[k] = removedValue(o, t, k)
+		}
+	}
+
+	for k, m := range modified {
+		o, ok := original[k]
+		if ok && reflect.DeepEqual(o, m) {
+			continue
+		}
+
+		ft, strategy := fieldType(t, k)
+		switch m := m.(type) {
+		case map[string]interface{}:
+			if o, ok := o.(map[string]interface{}); ok {
+				patch[k] = diffMaps(o, m, ft)
+				continue
+			}
+		case []interface{}:
+			if strategy == "merge" && ft != nil && ft.Kind() == reflect.Struct {
+				l := append([]interface{}{}, m...)
+				patch[k] = append(l, map[string]interface{}{"$patch": "replace"})
+				continue
+			}
+		}
+		patch[k] = m
+	}
+
+	return patch
+}
+
+// removedValue returns the patch value that removes the field of t stored
+// under the JSON key. Maps such as labels lose only the keys of v, the keys
+// that other tools or the server added are kept. Objects without a type, as
+// in manifests, can't tell maps from structs and treat all of them as maps.
+func removedValue(v interface{}, t reflect.Type, key string) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if t != nil {
+		ft, _ := fieldType(t, key)
+		if ft == nil || ft.Kind() != reflect.Map {
+			return nil
+		}
+	}
+
+	patch := make(map[string]interface{}, len(m))
+	for k := range m {
+		patch[k] = nil
+	}
+	return patch
+}
+
+// removedFields returns the patch that removes the fields of the last applied
+// configuration that are still in current but are no longer in modified.
+func removedFields(last, modified, current map[string]interface{}, t reflect.Type) map[string]interface{} {
+	patch := make(map[string]interface{})
+
+	for k, l := range last {
+		c, ok := current[k]
+		if !ok {
+			continue
+		}
+		m, ok := modified[k]
+		if !ok {
+			patch[k] = removedValue(l, t, k)
+			continue
+		}
+
+		lm, lok := l.(map[string]interface{})
+		mm, mok := m.(map[string]interface{})
+		cm, cok := c.(map[string]interface{})
+		if lok && mok && cok {
+			ft, _ := fieldType(t, k)
+			if p := removedFields(lm, mm, cm, ft); len(p) > 0 {
+				patch[k] = p
+			}
+		}
+	}
+
+	return patch
+}
+
+// mergePatches adds the changes of src to dst, values dst already replaces
+// are kept. The maps of dst are copied before they are changed, diffMaps
+// shares them with the modified object.
+func mergePatches(dst, src map[string]interface{}) {
+	for k, s := range src {
+		d, ok := dst[k]
+		if !ok {
+			dst[k] = s
+			continue
+		}
+		dm, dok := d.(map[string]interface{})
+		sm, sok := s.(map[string]interface{})
+		if dok && sok {
+			m := make(map[string]interface{}, len(dm)+len(sm))
+			for j, x := range dm {
+				m[j] = x
+			}
+			mergePatches(m, sm)
+			dst[k] = m
+		}
+	}
+}
+
+// fieldType returns the type of the value stored under the JSON key of t,
+// the element type for lists, along with its patch strategy.
+func fieldType(t reflect.Type, key string) (reflect.Type, string) {
+	if t == nil {
+		return nil, ""
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Map:
+		return elemType(t.Elem()), ""
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+			if name == "" && f.Anonymous {
+				if ft, strategy := fieldType(f.Type, key); ft != nil {
+					return ft, strategy
+				}
+				continue
+			}
+			if name == key {
+				return elemType(f.Type), f.Tag.Get("patchStrategy")
+			}
+		}
+	}
+
+	return nil, ""
+}
+
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,442 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api/v1"
+)
+
+func TestDiffMaps(t *testing.T) {
+	pod := reflect.TypeOf(v1.Pod{})
+
+	cases := []struct {
+		Original map[string]interface{}
+		Modified map[string]interface{}
+		Type     reflect.Type
+		Expected map[string]interface{}
+	}{
+		{
+			Original: map[string]interface{}{"metadata": map[string]interface{}{"name": "web"}},
+			Modified: map[string]interface{}{"metadata": map[string]interface{}{"name": "web"}},
+			Type:     pod,
+			Expected: map[string]interface{}{},
+		},
+		// removed map keys are set to null one by one
+		{
+			Original: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "web", "tier": "front"},
+				},
+			},
+			Modified: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "api"},
+				},
+			},
+			Type: pod,
+			Expected: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "api", "tier": nil},
+				},
+			},
+		},
+		// a removed map only loses the keys it had
+		{
+			Original: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "web"},
+				},
+			},
+			Modified: map[string]interface{}{
+				"metadata": map[string]interface{}{},
+			},
+			Type: pod,
+			Expected: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": nil},
+				},
+			},
+		},
+		// a removed struct is set to null
+		{
+			Original: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"securityContext": map[string]interface{}{"runAsUser": 1000},
+				},
+			},
+			Modified: map[string]interface{}{
+				"spec": map[string]interface{}{},
+			},
+			Type: pod,
+			Expected: map[string]interface{}{
+				"spec": map[string]interface{}{"securityContext": nil},
+			},
+		},
+		// lists merged by the server are replaced explicitly
+		{
+			Original: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "web", "image": "web:1"},
+						map[string]interface{}{"name": "log", "image": "log:1"},
+					},
+				},
+			},
+			Modified: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "web", "image": "web:2"},
+					},
+				},
+			},
+			Type: pod,
+			Expected: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "web", "image": "web:2"},
+						map[string]interface{}{"$patch": "replace"},
+					},
+				},
+			},
+		},
+		// objects without a type replace lists and treat all objects as maps
+		{
+			Original: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"hosts":  []interface{}{"a", "b"},
+					"config": map[string]interface{}{"debug": true, "port": 80},
+					"mode":   "fast",
+				},
+			},
+			Modified: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"hosts": []interface{}{"a"},
+				},
+			},
+			Expected: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"hosts":  []interface{}{"a"},
+					"config": map[string]interface{}{"debug": nil, "port": nil},
+					"mode":   nil,
+				},
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		actual := diffMaps(tc.Original, tc.Modified, tc.Type)
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestRemovedValue(t *testing.T) {
+	labels := map[string]interface{}{"app": "web", "tier": "front"}
+
+	cases := []struct {
+		Value    interface{}
+		Type     reflect.Type
+		Key      string
+		Expected interface{}
+	}{
+		{labels, reflect.TypeOf(v1.ObjectMeta{}), "labels", map[string]interface{}{"app": nil, "tier": nil}},
+		{labels, nil, "labels", map[string]interface{}{"app": nil, "tier": nil}},
+		{labels, reflect.TypeOf(v1.ObjectMeta{}), "unknown", nil},
+		{map[string]interface{}{"runAsUser": 1000}, reflect.TypeOf(v1.PodSpec{}), "securityContext", nil},
+		{"web", reflect.TypeOf(v1.ObjectMeta{}), "name", nil},
+		{"web", nil, "name", nil},
+	}
+
+	for i, tc := range cases {
+		actual := removedValue(tc.Value, tc.Type, tc.Key)
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestRemovedFields(t *testing.T) {
+	pod := reflect.TypeOf(v1.Pod{})
+
+	cases := []struct {
+		Last     map[string]interface{}
+		Modified map[string]interface{}
+		Current  map[string]interface{}
+		Type     reflect.Type
+		Expected map[string]interface{}
+	}{
+		// fields that are still configured are kept
+		{
+			Last: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "web"},
+				},
+			},
+			Modified: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "api"},
+				},
+			},
+			Current: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "web"},
+				},
+			},
+			Type:     pod,
+			Expected: map[string]interface{}{},
+		},
+		// fields that are no longer configured are removed, fields that
+		// were never applied are not
+		{
+			Last: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "web", "tier": "front"},
+				},
+				"spec": map[string]interface{}{"activeDeadlineSeconds": 30},
+			},
+			Modified: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "web"},
+				},
+				"spec": map[string]interface{}{},
+			},
+			Current: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "web", "tier": "front", "team": "ops"},
+				},
+				"spec": map[string]interface{}{"activeDeadlineSeconds": 30, "nodeName": "node-1"},
+			},
+			Type: pod,
+			Expected: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"tier": nil},
+				},
+				"spec": map[string]interface{}{"activeDeadlineSeconds": nil},
+			},
+		},
+		// a removed map only loses the applied keys
+		{
+			Last: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "web"},
+				},
+			},
+			Modified: map[string]interface{}{
+				"metadata": map[string]interface{}{},
+			},
+			Current: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "web", "team": "ops"},
+				},
+			},
+			Type: pod,
+			Expected: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": nil},
+				},
+			},
+		},
+		// fields that are already gone are left alone
+		{
+			Last: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "web"},
+				},
+			},
+			Modified: map[string]interface{}{
+				"metadata": map[string]interface{}{},
+			},
+			Current: map[string]interface{}{
+				"metadata": map[string]interface{}{},
+			},
+			Type:     pod,
+			Expected: map[string]interface{}{},
+		},
+		{
+			Last: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "web"},
+				},
+			},
+			Modified: map[string]interface{}{
+				"metadata": map[string]interface{}{},
+			},
+			Current: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "web", "team": "ops"},
+				},
+			},
+			Expected: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": nil},
+				},
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		actual := removedFields(tc.Last, tc.Modified, tc.Current, tc.Type)
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestThreeWayPatch(t *testing.T) {
+	cases := []struct {
+		Original map[string]interface{}
+		Modified map[string]interface{}
+		Current  map[string]interface{}
+		Expected map[string]interface{}
+		Err      bool
+	}{
+		// the first patch records the last applied configuration and
+		// keeps the labels of other tools
+		{
+			Original: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":   "web",
+					"labels": map[string]interface{}{"app": "web"},
+				},
+			},
+			Modified: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":   "web",
+					"labels": map[string]interface{}{"app": "api"},
+				},
+			},
+			Current: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":   "web",
+					"labels": map[string]interface{}{"app": "web", "team": "ops"},
+				},
+			},
+			Expected: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "api"},
+					"annotations": map[string]interface{}{
+						lastAppliedAnnotation: `{"metadata":{"labels":{"app":"api"},"name":"web"}}`,
+					},
+				},
+			},
+		},
+		// fields of the last apply are removed even when the original
+		// doesn't have them, as for adopted objects
+		{
+			Original: map[string]interface{}{},
+			Modified: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "web"},
+			},
+			Current: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":   "web",
+					"labels": map[string]interface{}{"app": "web", "team": "ops"},
+					"annotations": map[string]interface{}{
+						lastAppliedAnnotation: `{"metadata":{"labels":{"app":"web"},"name":"web"}}`,
+					},
+				},
+			},
+			Expected: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":   "web",
+					"labels": map[string]interface{}{"app": nil},
+					"annotations": map[string]interface{}{
+						lastAppliedAnnotation: `{"metadata":{"name":"web"}}`,
+					},
+				},
+			},
+		},
+		// the status is not part of the last applied configuration
+		{
+			Original: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "web"},
+			},
+			Modified: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "web"},
+				"status":   map[string]interface{}{"phase": "Running"},
+			},
+			Current: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name": "web",
+					"annotations": map[string]interface{}{
+						lastAppliedAnnotation: `{"metadata":{"name":"web"}}`,
+					},
+				},
+			},
+			Expected: map[string]interface{}{
+				"status": map[string]interface{}{"phase": "Running"},
+			},
+		},
+		{
+			Original: map[string]interface{}{},
+			Modified: map[string]interface{}{},
+			Current: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]interface{}{
+						lastAppliedAnnotation: `{"metadata":`,
+					},
+				},
+			},
+			Err: true,
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := threeWayPatch(tc.Original, tc.Modified, tc.Current, nil)
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: err: %v", i, err)
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestLastAppliedConfiguration(t *testing.T) {
+	cases := []struct {
+		Object   map[string]interface{}
+		Expected string
+	}{
+		{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata":   map[string]interface{}{"name": "web"},
+				"status":     map[string]interface{}{"phase": "Running"},
+			},
+			Expected: `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web"}}`,
+		},
+		// secret values are replaced by their digests
+		{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"data":       map[string]interface{}{"greeting": "aGVsbG8="},
+			},
+			Expected: `{"apiVersion":"v1","data":{"greeting":"sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},"kind":"Secret"}`,
+		},
+		{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"data":       map[string]interface{}{"greeting": "hello"},
+			},
+			Expected: `{"apiVersion":"v1","data":{"greeting":"hello"},"kind":"ConfigMap"}`,
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := lastAppliedConfiguration(tc.Object)
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if actual != tc.Expected {
+			t.Fatalf("%d: bad: %s", i, actual)
+		}
+	}
+}
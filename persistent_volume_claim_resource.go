@@ -0,0 +1,241 @@
+package main
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/validation"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+	"k8s.io/kubernetes/pkg/util/wait"
+)
+
+func persistentVolumeClaimResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "default",
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"annotations": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"access_modes": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"requests": {
+				Type:         schema.TypeMap,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateQuantityMap,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"volume_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"wait_until_bound": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"phase": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		Create: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace := r.Get("namespace").(string)
+			name := r.Get("name").(string)
+			claims := client.PersistentVolumeClaims(namespace)
+
+			item := &api.PersistentVolumeClaim{}
+			item.Name = name
+
+			err := writePersistentVolumeClaim(r, item)
+			if err != nil {
+				return err
+			}
+
+			err = writeLastApplied(client.RESTClient, item)
+			if err != nil {
+				return err
+			}
+
+			item, err = claims.Create(item)
+			if err != nil {
+				return err
+			}
+
+			r.SetId(join(namespace, name))
+
+			if r.Get("wait_until_bound").(bool) {
+				err = wait.Poll(1*time.Second, 5*time.Minute, func() (bool, error) {
+					item, err := claims.Get(name)
+					if err != nil {
+						return false, err
+					}
+					return item.Status.Phase == api.ClaimBound, nil
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			item, err = claims.Get(name)
+			if err != nil {
+				return err
+			}
+
+			r.Set("volume_name", item.Spec.VolumeName)
+			r.Set("phase", string(item.Status.Phase))
+			return nil
+		},
+		Read: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			item, err := client.PersistentVolumeClaims(namespace).Get(name)
+			if errors.IsNotFound(err) {
+				r.SetId("")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			readLabels(r, &item.ObjectMeta)
+			readAnnotations(r, &item.ObjectMeta)
+			readAccessModes(r, item.Spec.AccessModes)
+
+			r.Set("name", item.ObjectMeta.Name)
+			r.Set("requests", readResourceList(item.Spec.Resources.Requests, r.Get("requests")))
+			r.Set("volume_name", item.Spec.VolumeName)
+			r.Set("phase", string(item.Status.Phase))
+			return nil
+		},
+		Update: func(r *schema.ResourceData, v interface{}) error {
+
+			if !r.HasChange("labels") && !r.HasChange("annotations") {
+				return nil
+			}
+
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			// only the metadata of a claim can be changed, the spec is the
+			// same for both objects and stays out of the patch
+			return patchObject(client.RESTClient, "persistentvolumeclaims", r, v, func(r *schema.ResourceData) (runtime.Object, error) {
+				item := &api.PersistentVolumeClaim{}
+				item.Namespace = namespace
+				item.Name = name
+
+				err := writePersistentVolumeClaim(r, item)
+				return item, err
+			}, &api.PersistentVolumeClaim{})
+		},
+		Delete: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			return client.PersistentVolumeClaims(namespace).Delete(name)
+		},
+		Exists: func(r *schema.ResourceData, v interface{}) (bool, error) {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return false, err
+			}
+
+			_, err = client.PersistentVolumeClaims(namespace).Get(name)
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			return true, nil
+		},
+	}
+}
+
+func writePersistentVolumeClaim(r *schema.ResourceData, item *api.PersistentVolumeClaim) error {
+	writeLabels(r, &item.ObjectMeta)
+	writeAnnotations(r, &item.ObjectMeta)
+
+	requests, err := writeResourceList(r.Get("requests"))
+	if err != nil {
+		return err
+	}
+
+	item.Spec.AccessModes = writeAccessModes(r)
+	item.Spec.Resources.Requests = requests
+	if x, ok := r.GetOk("volume_name"); ok {
+		item.Spec.VolumeName = x.(string)
+	}
+
+	return nil
+}
+
+var persistentVolumeClaimFieldMappings = []fieldMapping{
+	{"metadata", ""},
+	{"spec.resources", "requests"},
+	{"spec", ""},
+}
+
+func validatePersistentVolumeClaimConfig(r *schema.ResourceData) field.ErrorList {
+	item := &api.PersistentVolumeClaim{}
+	item.Namespace = r.Get("namespace").(string)
+	item.Name = r.Get("name").(string)
+
+	err := writePersistentVolumeClaim(r, item)
+	if err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "resources"), "", err.Error())}
+	}
+
+	return validation.ValidatePersistentVolumeClaim(item)
+}
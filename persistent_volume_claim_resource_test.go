@@ -0,0 +1,82 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/resource"
+)
+
+func TestPersistentVolumeClaimReadQuantities(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+
+	claim := &api.PersistentVolumeClaim{}
+	claim.Namespace = "default"
+	claim.Name = "data"
+	claim.Spec.AccessModes = []api.PersistentVolumeAccessMode{api.ReadWriteOnce}
+	claim.Spec.Resources.Requests = api.ResourceList{
+		api.ResourceStorage: resource.MustParse("0.5Gi"),
+	}
+	s.put("/api/v1/namespaces/default/persistentvolumeclaims/data", claim)
+
+	cases := []struct {
+		State    string
+		Expected string
+	}{
+		// the server returns 512Mi, the configured form is kept
+		{"0.5Gi", "0.5Gi"},
+		{"524288Ki", "524288Ki"},
+		{"512Mi", "512Mi"},
+		{"1Gi", "512Mi"},
+	}
+
+	res := persistentVolumeClaimResource()
+	for _, tc := range cases {
+		r := resourceData(t, res, "default/data", map[string]string{
+			"namespace":        "default",
+			"name":             "data",
+			"requests.#":       "1",
+			"requests.storage": tc.State,
+		})
+
+		err := res.Read(r, s.meta())
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		actual := r.Get("requests").(map[string]interface{})["storage"]
+		if actual != tc.Expected {
+			t.Fatalf("%s: bad: %v", tc.State, actual)
+		}
+	}
+}
+
+func TestReadResourceList(t *testing.T) {
+	l := api.ResourceList{
+		api.ResourceCPU:    resource.MustParse("0.5"),
+		api.ResourceMemory: resource.MustParse("1Gi"),
+	}
+
+	cases := []struct {
+		Current  interface{}
+		Expected map[string]interface{}
+	}{
+		{nil, map[string]interface{}{"cpu": "500m", "memory": "1Gi"}},
+		{
+			map[string]interface{}{"cpu": "0.5", "memory": "1024Mi"},
+			map[string]interface{}{"cpu": "0.5", "memory": "1024Mi"},
+		},
+		{
+			map[string]interface{}{"cpu": "1", "memory": "lots", "storage": "1Gi"},
+			map[string]interface{}{"cpu": "500m", "memory": "1Gi"},
+		},
+	}
+
+	for i, tc := range cases {
+		actual := readResourceList(l, tc.Current)
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/validation"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+)
+
+func persistentVolumeResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"annotations": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"capacity": {
+				Type:         schema.TypeMap,
+				Required:     true,
+				ValidateFunc: validateQuantityMap,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"access_modes": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"reclaim_policy": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  string(api.PersistentVolumeReclaimRetain),
+			},
+			"host_path": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     hostPathVolumeResourceSpec,
+			},
+			"nfs": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     nfsVolumeResourceSpec,
+			},
+			"gce_persistent_disk": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     gcePersistentDiskVolumeResourceSpec,
+			},
+			"aws_elastic_block_store": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     awsElasticBlockStoreVolumeResourceSpec,
+			},
+
+			"phase": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		Create: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			name := r.Get("name").(string)
+
+			item := &api.PersistentVolume{}
+			item.Name = name
+
+			err := writePersistentVolume(r, item)
+			if err != nil {
+				return err
+			}
+
+			err = writeLastApplied(client.RESTClient, item)
+			if err != nil {
+				return err
+			}
+
+			item, err = client.PersistentVolumes().Create(item)
+			if err != nil {
+				return err
+			}
+
+			r.SetId(name)
+			r.Set("phase", string(item.Status.Phase))
+			return nil
+		},
+		Read: resourcePersistentVolumeRead,
+		Update: func(r *schema.ResourceData, v interface{}) error {
+
+			client := extractClient(v)
+			name := r.Id()
+
+			err := patchObject(client.RESTClient, "persistentvolumes", r, v, func(r *schema.ResourceData) (runtime.Object, error) {
+				item := &api.PersistentVolume{}
+				item.Name = name
+
+				err := writePersistentVolume(r, item)
+				return item, err
+			}, &api.PersistentVolume{})
+			if err != nil {
+				return err
+			}
+
+			return resourcePersistentVolumeRead(r, v)
+		},
+		Delete: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			name := r.Id()
+
+			return client.PersistentVolumes().Delete(name)
+		},
+		Exists: func(r *schema.ResourceData, v interface{}) (bool, error) {
+			client := extractClient(v)
+			name := r.Id()
+
+			_, err := client.PersistentVolumes().Get(name)
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			return true, nil
+		},
+	}
+}
+
+func resourcePersistentVolumeRead(r *schema.ResourceData, v interface{}) error {
+	client := extractClient(v)
+	name := r.Id()
+
+	item, err := client.PersistentVolumes().Get(name)
+	if errors.IsNotFound(err) {
+		r.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	readLabels(r, &item.ObjectMeta)
+	readAnnotations(r, &item.ObjectMeta)
+	readAccessModes(r, item.Spec.AccessModes)
+
+	r.Set("name", item.ObjectMeta.Name)
+	r.Set("capacity", readResourceList(item.Spec.Capacity, r.Get("capacity")))
+	r.Set("reclaim_policy", string(item.Spec.PersistentVolumeReclaimPolicy))
+	r.Set("phase", string(item.Status.Phase))
+
+	root := NewObjectBuilder(r, "")
+	readHostPathVolumeSource(root, item.Spec.HostPath)
+	readNFSVolumeSource(root, item.Spec.NFS)
+	readGCEPersistentDiskVolumeSource(root, item.Spec.GCEPersistentDisk)
+	readAWSElasticBlockStoreVolumeSource(root, item.Spec.AWSElasticBlockStore)
+	return root.Apply()
+}
+
+func writePersistentVolume(r *schema.ResourceData, item *api.PersistentVolume) error {
+	writeLabels(r, &item.ObjectMeta)
+	writeAnnotations(r, &item.ObjectMeta)
+
+	capacity, err := writeResourceList(r.Get("capacity"))
+	if err != nil {
+		return err
+	}
+
+	item.Spec.Capacity = capacity
+	item.Spec.AccessModes = writeAccessModes(r)
+	item.Spec.PersistentVolumeReclaimPolicy = api.PersistentVolumeReclaimPolicy(r.Get("reclaim_policy").(string))
+
+	item.Spec.HostPath = writeHostPathVolumeSource(r.Get("host_path"))
+	item.Spec.NFS = writeNFSVolumeSource(r.Get("nfs"))
+	item.Spec.GCEPersistentDisk = writeGCEPersistentDiskVolumeSource(r.Get("gce_persistent_disk"))
+	item.Spec.AWSElasticBlockStore = writeAWSElasticBlockStoreVolumeSource(r.Get("aws_elastic_block_store"))
+
+	return nil
+}
+
+var persistentVolumeFieldMappings = []fieldMapping{
+	{"metadata", ""},
+	{"spec.persistentVolumeReclaimPolicy", "reclaim_policy"},
+	{"spec", ""},
+}
+
+func validatePersistentVolumeConfig(r *schema.ResourceData) field.ErrorList {
+	item := &api.PersistentVolume{}
+	item.Name = r.Get("name").(string)
+
+	err := writePersistentVolume(r, item)
+	if err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "capacity"), "", err.Error())}
+	}
+
+	err = applyDefaults(item, "v1")
+	if err != nil {
+		return field.ErrorList{field.InternalError(field.NewPath("spec"), err)}
+	}
+
+	return validation.ValidatePersistentVolume(item)
+}
+
+func readAccessModes(r *schema.ResourceData, modes []api.PersistentVolumeAccessMode) {
+	var l []interface{}
+	for _, x := range modes {
+		l = append(l, string(x))
+	}
+	r.Set("access_modes", l)
+}
+
+func writeAccessModes(r *schema.ResourceData) []api.PersistentVolumeAccessMode {
+	var modes []api.PersistentVolumeAccessMode
+	if l, _ := r.Get("access_modes").([]interface{}); l != nil {
+		for _, x := range l {
+			if s, ok := x.(string); ok {
+				modes = append(modes, api.PersistentVolumeAccessMode(s))
+			}
+		}
+	}
+	return modes
+}
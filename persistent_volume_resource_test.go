@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/resource"
+)
+
+// TestPersistentVolumeUpdate updates a persistent volume that was bound since
+// the last refresh. The capacity is stored in canonical form.
+func TestPersistentVolumeUpdate(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+
+	item := &api.PersistentVolume{}
+	item.Annotations = map[string]string{ownedAnnotation: defaultOwner}
+	item.Spec.Capacity = api.ResourceList{api.ResourceStorage: resource.MustParse("512Mi")}
+	item.Spec.AccessModes = []api.PersistentVolumeAccessMode{api.ReadWriteOnce}
+	item.Spec.PersistentVolumeReclaimPolicy = api.PersistentVolumeReclaimRetain
+	item.Spec.HostPath = &api.HostPathVolumeSource{Path: "/data"}
+	item.Status.Phase = api.VolumeBound
+	s.put("/api/v1/persistentvolumes/data", item)
+
+	state := &terraform.InstanceState{
+		ID: "data",
+		Attributes: map[string]string{
+			"name":             "data",
+			"owner":            defaultOwner,
+			"capacity.#":       "1",
+			"capacity.storage": "0.5Gi",
+			"access_modes.#":   "1",
+			"access_modes.0":   "ReadWriteOnce",
+			"reclaim_policy":   "Retain",
+			"host_path.#":      "1",
+			"host_path.0.path": "/data",
+			"phase":            "Available",
+		},
+	}
+	raw := map[string]interface{}{
+		"name":           "data",
+		"capacity":       map[string]interface{}{"storage": "0.5Gi"},
+		"access_modes":   []interface{}{"ReadWriteOnce"},
+		"reclaim_policy": "Recycle",
+		"host_path": []interface{}{
+			map[string]interface{}{"path": "/data"},
+		},
+	}
+
+	res := providerResource(persistentVolumeResource())
+	state, err := apply(t, res, state, raw, s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]string{
+		"capacity.storage": "0.5Gi",
+		"reclaim_policy":   "Recycle",
+		"phase":            "Bound",
+	}
+	for k, x := range expected {
+		if state.Attributes[k] != x {
+			t.Fatalf("bad %s: %q", k, state.Attributes[k])
+		}
+	}
+}
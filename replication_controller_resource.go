@@ -11,9 +11,12 @@ import (
 	"k8s.io/kubernetes/pkg/api"
 	"k8s.io/kubernetes/pkg/api/errors"
 	"k8s.io/kubernetes/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/api/validation"
 	"k8s.io/kubernetes/pkg/client/unversioned"
 	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/runtime"
 	"k8s.io/kubernetes/pkg/util/intstr"
+	"k8s.io/kubernetes/pkg/util/validation/field"
 	"k8s.io/kubernetes/pkg/util/wait"
 )
 
@@ -458,6 +461,259 @@ var containerResourceSpec = &schema.Resource{
 	},
 }
 
+var podTemplateResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"labels": {
+			Type:     schema.TypeMap,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+		"node_selector": {
+			Type:     schema.TypeMap,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+		"volume": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+
+					"name": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+
+					"host_path": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     hostPathVolumeResourceSpec,
+					},
+
+					"empty_dir": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"medium": {
+									Type:     schema.TypeString,
+									Required: true,
+								},
+							},
+						},
+					},
+
+					"gce_persistent_disk": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     gcePersistentDiskVolumeResourceSpec,
+					},
+
+					"aws_elastic_block_store": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     awsElasticBlockStoreVolumeResourceSpec,
+					},
+
+					"git_repo": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"repository": {
+									Type:     schema.TypeString,
+									Required: true,
+								},
+								"revision": {
+									Type:     schema.TypeString,
+									Required: true,
+								},
+								"directory": {
+									Type:     schema.TypeString,
+									Required: true,
+								},
+							},
+						},
+					},
+
+					"secret": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"secret_name": {
+									Type:     schema.TypeString,
+									Required: true,
+								},
+							},
+						},
+					},
+
+					"persistent_volume_claim": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     persistentVolumeClaimVolumeResourceSpec,
+					},
+
+					"nfs": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     nfsVolumeResourceSpec,
+					},
+
+					"iscsi": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     iscsiVolumeResourceSpec,
+					},
+
+					"glusterfs": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     glusterfsVolumeResourceSpec,
+					},
+
+					"rbd": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     rbdVolumeResourceSpec,
+					},
+
+					"flex_volume": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     flexVolumeResourceSpec,
+					},
+
+					"cinder": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     cinderVolumeResourceSpec,
+					},
+
+					"ceph_fs": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     cephFSVolumeResourceSpec,
+					},
+
+					"flocker": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     flockerVolumeResourceSpec,
+					},
+
+					"downward_api": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     downwardAPIVolumeResourceSpec,
+					},
+
+					"fc": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     fcVolumeResourceSpec,
+					},
+
+					"azure_file": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     azureFileVolumeResourceSpec,
+					},
+
+					"config_map": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     configMapVolumeResourceSpec,
+					},
+				},
+			},
+		},
+		"image_pull_secret": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+				},
+			},
+		},
+		"container": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem:     containerResourceSpec,
+		},
+		"restart_policy": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"service_account_name": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"node_name": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"dns_policy": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"termination_grace_period": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Computed: true,
+		},
+		"active_deadline": {
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+	},
+}
+
+var rolloutResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"settle_duration": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "30s",
+			ValidateFunc: validateDuration,
+		},
+		"step_timeout": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "2m",
+			ValidateFunc: validateDuration,
+		},
+		"step_size": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  1,
+		},
+		"max_surge": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  1,
+		},
+		"rollback_on_failure": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	},
+}
+
 func replicationControllerResource() *schema.Resource {
 	return &schema.Resource{
 		Schema: map[string]*schema.Schema{
@@ -494,212 +750,31 @@ func replicationControllerResource() *schema.Resource {
 				Type:     schema.TypeInt,
 				Optional: true,
 			},
+			"replicas_initial_only": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"orphan_pods": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"delete_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "2m",
+				ValidateFunc: validateDuration,
+			},
+			"rollout": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     rolloutResourceSpec,
+			},
 			"template": {
 				Type:     schema.TypeList,
 				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"labels": {
-							Type:     schema.TypeMap,
-							Optional: true,
-							Elem: &schema.Schema{
-								Type:     schema.TypeString,
-								Required: true,
-							},
-						},
-						"node_selector": {
-							Type:     schema.TypeMap,
-							Optional: true,
-							Elem: &schema.Schema{
-								Type:     schema.TypeString,
-								Required: true,
-							},
-						},
-						"volume": {
-							Type:     schema.TypeList,
-							Optional: true,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-
-									"name": {
-										Type:     schema.TypeString,
-										Required: true,
-									},
-
-									"host_path": {
-										Type:     schema.TypeList,
-										Optional: true,
-										Elem: &schema.Resource{
-											Schema: map[string]*schema.Schema{
-												"path": {
-													Type:     schema.TypeString,
-													Required: true,
-												},
-											},
-										},
-									},
-
-									"empty_dir": {
-										Type:     schema.TypeList,
-										Optional: true,
-										Elem: &schema.Resource{
-											Schema: map[string]*schema.Schema{
-												"medium": {
-													Type:     schema.TypeString,
-													Required: true,
-												},
-											},
-										},
-									},
-
-									"gce_persistent_disk": {
-										Type:     schema.TypeList,
-										Optional: true,
-										Elem: &schema.Resource{
-											Schema: map[string]*schema.Schema{
-												"pd_name": {
-													Type:     schema.TypeString,
-													Required: true,
-												},
-												"fs_type": {
-													Type:     schema.TypeString,
-													Required: true,
-												},
-												"partition": {
-													Type:     schema.TypeInt,
-													Required: true,
-												},
-												"read_only": {
-													Type:     schema.TypeBool,
-													Required: true,
-												},
-											},
-										},
-									},
-
-									"aws_elastic_block_store": {
-										Type:     schema.TypeList,
-										Optional: true,
-										Elem: &schema.Resource{
-											Schema: map[string]*schema.Schema{
-												"volume_id": {
-													Type:     schema.TypeString,
-													Required: true,
-												},
-												"fs_type": {
-													Type:     schema.TypeString,
-													Required: true,
-												},
-												"partition": {
-													Type:     schema.TypeInt,
-													Required: true,
-												},
-												"read_only": {
-													Type:     schema.TypeBool,
-													Required: true,
-												},
-											},
-										},
-									},
-
-									"git_repo": {
-										Type:     schema.TypeList,
-										Optional: true,
-										Elem: &schema.Resource{
-											Schema: map[string]*schema.Schema{
-												"repository": {
-													Type:     schema.TypeString,
-													Required: true,
-												},
-												"revision": {
-													Type:     schema.TypeString,
-													Required: true,
-												},
-												"directory": {
-													Type:     schema.TypeString,
-													Required: true,
-												},
-											},
-										},
-									},
-
-									"secret": {
-										Type:     schema.TypeList,
-										Optional: true,
-										Elem: &schema.Resource{
-											Schema: map[string]*schema.Schema{
-												"secret_name": {
-													Type:     schema.TypeString,
-													Required: true,
-												},
-											},
-										},
-									},
-
-									// TODO:
-									// NFS *NFSVolumeSource
-									// ISCSI *ISCSIVolumeSource
-									// Glusterfs *GlusterfsVolumeSource
-									// PersistentVolumeClaim *PersistentVolumeClaimVolumeSource
-									// RBD *RBDVolumeSource
-									// FlexVolume *FlexVolumeSource
-									// Cinder *CinderVolumeSource
-									// CephFS *CephFSVolumeSource
-									// Flocker *FlockerVolumeSource
-									// DownwardAPI *DownwardAPIVolumeSource
-									// FC *FCVolumeSource
-									// AzureFile *AzureFileVolumeSource
-									// ConfigMap *ConfigMapVolumeSource
-
-								},
-							},
-						},
-						"image_pull_secret": {
-							Type:     schema.TypeList,
-							Optional: true,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"name": {
-										Type:     schema.TypeString,
-										Required: true,
-									},
-								},
-							},
-						},
-						"container": {
-							Type:     schema.TypeList,
-							Optional: true,
-							Elem:     containerResourceSpec,
-						},
-						"restart_policy": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Computed: true,
-						},
-						"service_account_name": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"node_name": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"dns_policy": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Computed: true,
-						},
-						"termination_grace_period": {
-							Type:     schema.TypeInt,
-							Optional: true,
-							Computed: true,
-						},
-						"active_deadline": {
-							Type:     schema.TypeInt,
-							Optional: true,
-						},
-					},
-				},
+				Elem:     podTemplateResourceSpec,
 			},
 		},
 
@@ -713,9 +788,27 @@ func replicationControllerResource() *schema.Resource {
 
 func resourceControllerRead(r *schema.ResourceData, v interface{}) error {
 	client := extractClient(v)
-	namespace, id := split(r.Id())
+	namespace, id, err := split(r.Id())
+	if err != nil {
+		return err
+	}
 
 	item, err := client.ReplicationControllers(namespace).Get(id)
+	if errors.IsNotFound(err) {
+		// an interrupted swap removed the original RC, its replacement
+		// takes over once the next Update or Delete recovers the rollout
+		var tmp *api.ReplicationController
+		tmp, err = findControllerRollout(client.ReplicationControllers(namespace), id)
+		if err == nil && tmp != nil {
+			item = tmp
+		} else if err == nil {
+			err = errors.NewNotFound(api.Resource("replicationcontrollers"), id)
+		}
+	}
+	if errors.IsNotFound(err) {
+		r.SetId("")
+		return nil
+	}
 	if err != nil {
 		return err
 	}
@@ -728,294 +821,279 @@ func resourceControllerRead(r *schema.ResourceData, v interface{}) error {
 	root := NewObjectBuilder(r, "")
 	t := root.NewList("template")
 
-	root.Set("replicas", item.Spec.Replicas)
+	// an autoscaler owns the replica count after creation
+	if !r.Get("replicas_initial_only").(bool) {
+		root.Set("replicas", item.Spec.Replicas)
+	}
 
 	if tmpl := item.Spec.Template; tmpl != nil {
+		delete(tmpl.ObjectMeta.Labels, "deployment")
+		readPodTemplateSpec(t, tmpl)
 
-		t.Set("restart_policy", string(tmpl.Spec.RestartPolicy))
-		t.Set("dns_policy", string(tmpl.Spec.DNSPolicy))
-		t.Set("service_account_name", tmpl.Spec.ServiceAccountName)
-		t.Set("node_name", tmpl.Spec.NodeName)
-
-		if tmpl.Spec.TerminationGracePeriodSeconds != nil {
-			t.Set("termination_grace_period", int(*tmpl.Spec.TerminationGracePeriodSeconds))
+		t.Apply()
+		err = root.Apply()
+		if err != nil {
+			panic(err)
 		}
+	} else {
+		r.Set("template", nil)
+	}
 
-		if tmpl.Spec.ActiveDeadlineSeconds != nil {
-			t.Set("active_deadline", int(*tmpl.Spec.ActiveDeadlineSeconds))
-		}
+	return nil
+}
 
-		if tmpl.Spec.NodeSelector != nil {
-			var nodeSelector = map[string]interface{}{}
-			for k, v := range tmpl.Spec.NodeSelector {
-				nodeSelector[k] = v
-			}
-			t.Set("node_selector", nodeSelector)
+func readPodTemplateSpec(t *ListBuilder, tmpl *api.PodTemplateSpec) {
+	t.Set("restart_policy", string(tmpl.Spec.RestartPolicy))
+	t.Set("dns_policy", string(tmpl.Spec.DNSPolicy))
+	t.Set("service_account_name", tmpl.Spec.ServiceAccountName)
+	t.Set("node_name", tmpl.Spec.NodeName)
+
+	if tmpl.Spec.TerminationGracePeriodSeconds != nil {
+		t.Set("termination_grace_period", int(*tmpl.Spec.TerminationGracePeriodSeconds))
+	}
+
+	if tmpl.Spec.ActiveDeadlineSeconds != nil {
+		t.Set("active_deadline", int(*tmpl.Spec.ActiveDeadlineSeconds))
+	}
+
+	if tmpl.Spec.NodeSelector != nil {
+		var nodeSelector = map[string]interface{}{}
+		for k, v := range tmpl.Spec.NodeSelector {
+			nodeSelector[k] = v
 		}
+		t.Set("node_selector", nodeSelector)
+	}
 
-		if tmpl.ObjectMeta.Labels != nil {
-			var labels = map[string]interface{}{}
-			for k, v := range tmpl.ObjectMeta.Labels {
-				if k == "deployment" {
-					continue
-				}
-				labels[k] = v
-			}
-			t.Set("labels", labels)
+	if tmpl.ObjectMeta.Labels != nil {
+		var labels = map[string]interface{}{}
+		for k, v := range tmpl.ObjectMeta.Labels {
+			labels[k] = v
 		}
+		t.Set("labels", labels)
+	}
 
-		vol := t.NewList("volume")
-		for _, volume := range tmpl.Spec.Volumes {
-			vol.Next()
-			vol.Set("name", volume.Name)
+	vol := t.NewList("volume")
+	for _, volume := range tmpl.Spec.Volumes {
+		vol.Next()
+		vol.Set("name", volume.Name)
 
-			if volume.HostPath != nil {
-				x := vol.NewList("host_path")
-				x.Set("path", volume.HostPath.Path)
-				x.Apply()
-			}
+		readHostPathVolumeSource(vol, volume.HostPath)
 
-			if volume.EmptyDir != nil {
-				x := vol.NewList("empty_dir")
-				x.Set("medium", string(volume.EmptyDir.Medium))
-				x.Apply()
-			}
+		if volume.EmptyDir != nil {
+			x := vol.NewList("empty_dir")
+			x.Set("medium", string(volume.EmptyDir.Medium))
+			x.Apply()
+		}
 
-			if volume.GCEPersistentDisk != nil {
-				x := vol.NewList("gce_persistent_disk")
-				x.Set("pd_name", volume.GCEPersistentDisk.PDName)
-				x.Set("fs_type", volume.GCEPersistentDisk.FSType)
-				x.Set("partition", volume.GCEPersistentDisk.Partition)
-				x.Set("read_only", volume.GCEPersistentDisk.ReadOnly)
-				x.Apply()
-			}
+		readGCEPersistentDiskVolumeSource(vol, volume.GCEPersistentDisk)
+		readAWSElasticBlockStoreVolumeSource(vol, volume.AWSElasticBlockStore)
 
-			if volume.AWSElasticBlockStore != nil {
-				x := vol.NewList("aws_elastic_block_store")
-				x.Set("volume_id", volume.AWSElasticBlockStore.VolumeID)
-				x.Set("fs_type", volume.AWSElasticBlockStore.FSType)
-				x.Set("partition", volume.AWSElasticBlockStore.Partition)
-				x.Set("read_only", volume.AWSElasticBlockStore.ReadOnly)
-				x.Apply()
-			}
+		if volume.GitRepo != nil {
+			x := vol.NewList("git_repo")
+			x.Set("repository", volume.GitRepo.Repository)
+			x.Set("revision", volume.GitRepo.Revision)
+			x.Set("directory", volume.GitRepo.Directory)
+			x.Apply()
+		}
 
-			if volume.GitRepo != nil {
-				x := vol.NewList("git_repo")
-				x.Set("repository", volume.GitRepo.Repository)
-				x.Set("revision", volume.GitRepo.Revision)
-				x.Set("directory", volume.GitRepo.Directory)
-				x.Apply()
-			}
+		if volume.Secret != nil {
+			x := vol.NewList("secret")
+			x.Set("secret_name", volume.Secret.SecretName)
+			x.Apply()
+		}
 
-			if volume.Secret != nil {
-				x := vol.NewList("secret")
-				x.Set("secret_name", volume.Secret.SecretName)
-				x.Apply()
-			}
+		readPersistentVolumeClaimVolumeSource(vol, volume.PersistentVolumeClaim)
+		readNFSVolumeSource(vol, volume.NFS)
+		readISCSIVolumeSource(vol, volume.ISCSI)
+		readGlusterfsVolumeSource(vol, volume.Glusterfs)
+		readRBDVolumeSource(vol, volume.RBD)
+		readFlexVolumeSource(vol, volume.FlexVolume)
+		readCinderVolumeSource(vol, volume.Cinder)
+		readCephFSVolumeSource(vol, volume.CephFS)
+		readFlockerVolumeSource(vol, volume.Flocker)
+		readDownwardAPIVolumeSource(vol, volume.DownwardAPI)
+		readFCVolumeSource(vol, volume.FC)
+		readAzureFileVolumeSource(vol, volume.AzureFile)
+		readConfigMapVolumeSource(vol, volume.ConfigMap)
+	}
+	vol.Apply()
 
-			// TODO:
-			// NFS *NFSVolumeSource
-			// ISCSI *ISCSIVolumeSource
-			// Glusterfs *GlusterfsVolumeSource
-			// PersistentVolumeClaim *PersistentVolumeClaimVolumeSource
-			// RBD *RBDVolumeSource
-			// FlexVolume *FlexVolumeSource
-			// Cinder *CinderVolumeSource
-			// CephFS *CephFSVolumeSource
-			// Flocker *FlockerVolumeSource
-			// DownwardAPI *DownwardAPIVolumeSource
-			// FC *FCVolumeSource
-			// AzureFile *AzureFileVolumeSource
-			// ConfigMap *ConfigMapVolumeSource
-		}
-		vol.Apply()
-
-		imagePullSecret := t.NewList("image_pull_secret")
-		for _, x := range tmpl.Spec.ImagePullSecrets {
-			imagePullSecret.Next()
-			imagePullSecret.Set("name", x.Name)
-		}
-		imagePullSecret.Apply()
-
-		c := t.NewList("container")
-		for _, container := range tmpl.Spec.Containers {
-			c.Next()
-			c.Set("name", container.Name)
-			c.Set("image", container.Image)
-			c.Set("image_pull_policy", string(container.ImagePullPolicy))
-			c.Set("termination_message_path", container.TerminationMessagePath)
-			c.Set("working_dir", container.WorkingDir)
-
-			if container.Command != nil {
-				c.Set("command", container.Command)
-			}
+	imagePullSecret := t.NewList("image_pull_secret")
+	for _, x := range tmpl.Spec.ImagePullSecrets {
+		imagePullSecret.Next()
+		imagePullSecret.Set("name", x.Name)
+	}
+	imagePullSecret.Apply()
 
-			if container.Args != nil {
-				c.Set("args", container.Args)
-			}
+	c := t.NewList("container")
+	for _, container := range tmpl.Spec.Containers {
+		c.Next()
+		c.Set("name", container.Name)
+		c.Set("image", container.Image)
+		c.Set("image_pull_policy", string(container.ImagePullPolicy))
+		c.Set("termination_message_path", container.TerminationMessagePath)
+		c.Set("working_dir", container.WorkingDir)
 
-			if container.Ports != nil {
-				port := c.NewList("port")
-				for _, v := range container.Ports {
-					port.Next()
-					port.Set("name", v.Name)
-					port.Set("host_port", v.HostPort)
-					port.Set("host_ip", v.HostIP)
-					port.Set("container_port", v.ContainerPort)
-					port.Set("protocol", string(v.Protocol))
-				}
-				port.Apply()
-			}
+		if container.Command != nil {
+			c.Set("command", container.Command)
+		}
 
-			if container.Env != nil {
-				env := c.NewList("env")
-				for _, x := range container.Env {
-					env.Next()
-					env.Set("name", x.Name)
-					if x.ValueFrom == nil {
-						env.Set("value", x.Value)
-					} else {
-						valueFrom := env.NewList("value_from")
-						if x.ValueFrom.FieldRef != nil {
-							fieldRef := valueFrom.NewList("field_ref")
-							fieldRef.Set("field_path", x.ValueFrom.FieldRef.FieldPath)
-							fieldRef.Apply()
-						}
-						if x.ValueFrom.ConfigMapKeyRef != nil {
-							configMapKeyRef := valueFrom.NewList("config_map_key_ref")
-							configMapKeyRef.Set("name", x.ValueFrom.ConfigMapKeyRef.Name)
-							configMapKeyRef.Set("key", x.ValueFrom.ConfigMapKeyRef.Key)
-							configMapKeyRef.Apply()
-						}
-						if x.ValueFrom.SecretKeyRef != nil {
-							secretKeyRef := valueFrom.NewList("secret_key_ref")
-							secretKeyRef.Set("name", x.ValueFrom.SecretKeyRef.Name)
-							secretKeyRef.Set("key", x.ValueFrom.SecretKeyRef.Key)
-							secretKeyRef.Apply()
-						}
-						valueFrom.Apply()
+		if container.Args != nil {
+			c.Set("args", container.Args)
+		}
+
+		if container.Ports != nil {
+			port := c.NewList("port")
+			for _, v := range container.Ports {
+				port.Next()
+				port.Set("name", v.Name)
+				port.Set("host_port", v.HostPort)
+				port.Set("host_ip", v.HostIP)
+				port.Set("container_port", v.ContainerPort)
+				port.Set("protocol", string(v.Protocol))
+			}
+			port.Apply()
+		}
+
+		if container.Env != nil {
+			env := c.NewList("env")
+			for _, x := range container.Env {
+				env.Next()
+				env.Set("name", x.Name)
+				if x.ValueFrom == nil {
+					env.Set("value", x.Value)
+				} else {
+					valueFrom := env.NewList("value_from")
+					if x.ValueFrom.FieldRef != nil {
+						fieldRef := valueFrom.NewList("field_ref")
+						fieldRef.Set("field_path", x.ValueFrom.FieldRef.FieldPath)
+						fieldRef.Apply()
+					}
+					if x.ValueFrom.ConfigMapKeyRef != nil {
+						configMapKeyRef := valueFrom.NewList("config_map_key_ref")
+						configMapKeyRef.Set("name", x.ValueFrom.ConfigMapKeyRef.Name)
+						configMapKeyRef.Set("key", x.ValueFrom.ConfigMapKeyRef.Key)
+						configMapKeyRef.Apply()
 					}
+					if x.ValueFrom.SecretKeyRef != nil {
+						secretKeyRef := valueFrom.NewList("secret_key_ref")
+						secretKeyRef.Set("name", x.ValueFrom.SecretKeyRef.Name)
+						secretKeyRef.Set("key", x.ValueFrom.SecretKeyRef.Key)
+						secretKeyRef.Apply()
+					}
+					valueFrom.Apply()
 				}
-				env.Apply()
 			}
+			env.Apply()
+		}
 
-			if container.VolumeMounts != nil {
-				volumeMount := c.NewList("volume_mount")
-				for _, x := range container.VolumeMounts {
-					volumeMount.Next()
-					volumeMount.Set("name", x.Name)
-					volumeMount.Set("read_only", x.ReadOnly)
-					volumeMount.Set("mount_path", x.MountPath)
-				}
-				volumeMount.Apply()
+		if container.VolumeMounts != nil {
+			volumeMount := c.NewList("volume_mount")
+			for _, x := range container.VolumeMounts {
+				volumeMount.Next()
+				volumeMount.Set("name", x.Name)
+				volumeMount.Set("read_only", x.ReadOnly)
+				volumeMount.Set("mount_path", x.MountPath)
 			}
+			volumeMount.Apply()
+		}
 
-			resources := c.NewList("resources")
-			limits := resources.NewList("limits")
-			if x := container.Resources.Limits.Cpu(); x != nil && x.Value() != 0 {
-				limits.Set("cpu", x.String())
-			}
-			if x := container.Resources.Limits.Memory(); x != nil && x.Value() != 0 {
-				limits.Set("memory", x.String())
-			}
-			limits.Apply()
+		resources := c.NewList("resources")
+		limits := resources.NewList("limits")
+		if x := container.Resources.Limits.Cpu(); x != nil && x.Value() != 0 {
+			limits.Set("cpu", x.String())
+		}
+		if x := container.Resources.Limits.Memory(); x != nil && x.Value() != 0 {
+			limits.Set("memory", x.String())
+		}
+		limits.Apply()
 
-			requests := resources.NewList("requests")
-			if x := container.Resources.Requests.Cpu(); x != nil && x.Value() != 0 {
-				requests.Set("cpu", x.String())
-			}
-			if x := container.Resources.Requests.Memory(); x != nil && x.Value() != 0 {
-				requests.Set("memory", x.String())
+		requests := resources.NewList("requests")
+		if x := container.Resources.Requests.Cpu(); x != nil && x.Value() != 0 {
+			requests.Set("cpu", x.String())
+		}
+		if x := container.Resources.Requests.Memory(); x != nil && x.Value() != 0 {
+			requests.Set("memory", x.String())
+		}
+		requests.Apply()
+		resources.Apply()
+
+		if x := container.LivenessProbe; x != nil {
+			livenessProbe := c.NewList("liveness_probe")
+			livenessProbe.Set("initial_delay", x.InitialDelaySeconds)
+			livenessProbe.Set("timeout", x.TimeoutSeconds)
+			livenessProbe.Set("period", x.PeriodSeconds)
+			livenessProbe.Set("success_threshold", x.SuccessThreshold)
+			livenessProbe.Set("failure_threshold", x.FailureThreshold)
+
+			if y := x.Exec; y != nil {
+				exec := livenessProbe.NewList("exec")
+				exec.Set("command", y.Command)
+				exec.Apply()
 			}
-			requests.Apply()
-			resources.Apply()
-
-			if x := container.LivenessProbe; x != nil {
-				livenessProbe := c.NewList("liveness_probe")
-				livenessProbe.Set("initial_delay", x.InitialDelaySeconds)
-				livenessProbe.Set("timeout", x.TimeoutSeconds)
-				livenessProbe.Set("period", x.PeriodSeconds)
-				livenessProbe.Set("success_threshold", x.SuccessThreshold)
-				livenessProbe.Set("failure_threshold", x.FailureThreshold)
-
-				if y := x.Exec; y != nil {
-					exec := livenessProbe.NewList("exec")
-					exec.Set("command", y.Command)
-					exec.Apply()
-				}
-				if y := x.HTTPGet; y != nil {
-					httpGet := livenessProbe.NewList("http_get")
-					httpGet.Set("path", y.Path)
-					httpGet.Set("port", y.Port.IntValue())
-					httpGet.Set("host", y.Host)
-					httpGet.Set("scheme", string(y.Scheme))
-					httpHeader := httpGet.NewList("http_header")
-					for _, h := range y.HTTPHeaders {
-						httpHeader.Next()
-						httpHeader.Set("name", h.Name)
-						httpHeader.Set("value", h.Value)
-					}
-					httpHeader.Apply()
-					httpGet.Apply()
+			if y := x.HTTPGet; y != nil {
+				httpGet := livenessProbe.NewList("http_get")
+				httpGet.Set("path", y.Path)
+				httpGet.Set("port", y.Port.IntValue())
+				httpGet.Set("host", y.Host)
+				httpGet.Set("scheme", string(y.Scheme))
+				httpHeader := httpGet.NewList("http_header")
+				for _, h := range y.HTTPHeaders {
+					httpHeader.Next()
+					httpHeader.Set("name", h.Name)
+					httpHeader.Set("value", h.Value)
 				}
-				if y := x.TCPSocket; y != nil {
-					tcpSocket := livenessProbe.NewList("tcp_socket")
-					tcpSocket.Set("port", y.Port.IntValue())
-					tcpSocket.Apply()
-				}
-
-				livenessProbe.Apply()
+				httpHeader.Apply()
+				httpGet.Apply()
+			}
+			if y := x.TCPSocket; y != nil {
+				tcpSocket := livenessProbe.NewList("tcp_socket")
+				tcpSocket.Set("port", y.Port.IntValue())
+				tcpSocket.Apply()
 			}
 
-			if x := container.ReadinessProbe; x != nil {
-				readinessProbe := c.NewList("readiness_probe")
-				readinessProbe.Set("initial_delay", x.InitialDelaySeconds)
-				readinessProbe.Set("timeout", x.TimeoutSeconds)
-				readinessProbe.Set("period", x.PeriodSeconds)
-				readinessProbe.Set("success_threshold", x.SuccessThreshold)
-				readinessProbe.Set("failure_threshold", x.FailureThreshold)
-
-				if y := x.Exec; y != nil {
-					exec := readinessProbe.NewList("exec")
-					exec.Set("command", y.Command)
-					exec.Apply()
-				}
-				if y := x.HTTPGet; y != nil {
-					httpGet := readinessProbe.NewList("http_get")
-					httpGet.Set("path", y.Path)
-					httpGet.Set("port", y.Port.IntValue())
-					httpGet.Set("host", y.Host)
-					httpGet.Set("scheme", string(y.Scheme))
-					httpHeader := httpGet.NewList("http_header")
-					for _, h := range y.HTTPHeaders {
-						httpHeader.Next()
-						httpHeader.Set("name", h.Name)
-						httpHeader.Set("value", h.Value)
-					}
-					httpHeader.Apply()
-					httpGet.Apply()
-				}
-				if y := x.TCPSocket; y != nil {
-					tcpSocket := readinessProbe.NewList("tcp_socket")
-					tcpSocket.Set("port", y.Port.IntValue())
-					tcpSocket.Apply()
-				}
+			livenessProbe.Apply()
+		}
 
-				readinessProbe.Apply()
+		if x := container.ReadinessProbe; x != nil {
+			readinessProbe := c.NewList("readiness_probe")
+			readinessProbe.Set("initial_delay", x.InitialDelaySeconds)
+			readinessProbe.Set("timeout", x.TimeoutSeconds)
+			readinessProbe.Set("period", x.PeriodSeconds)
+			readinessProbe.Set("success_threshold", x.SuccessThreshold)
+			readinessProbe.Set("failure_threshold", x.FailureThreshold)
+
+			if y := x.Exec; y != nil {
+				exec := readinessProbe.NewList("exec")
+				exec.Set("command", y.Command)
+				exec.Apply()
+			}
+			if y := x.HTTPGet; y != nil {
+				httpGet := readinessProbe.NewList("http_get")
+				httpGet.Set("path", y.Path)
+				httpGet.Set("port", y.Port.IntValue())
+				httpGet.Set("host", y.Host)
+				httpGet.Set("scheme", string(y.Scheme))
+				httpHeader := httpGet.NewList("http_header")
+				for _, h := range y.HTTPHeaders {
+					httpHeader.Next()
+					httpHeader.Set("name", h.Name)
+					httpHeader.Set("value", h.Value)
+				}
+				httpHeader.Apply()
+				httpGet.Apply()
+			}
+			if y := x.TCPSocket; y != nil {
+				tcpSocket := readinessProbe.NewList("tcp_socket")
+				tcpSocket.Set("port", y.Port.IntValue())
+				tcpSocket.Apply()
 			}
-		}
 
-		c.Apply()
-		t.Apply()
-		err = root.Apply()
-		if err != nil {
-			panic(err)
+			readinessProbe.Apply()
 		}
-	} else {
-		r.Set("template", nil)
 	}
 
-	return nil
+	c.Apply()
 }
 
 func resourceControllerCreate(r *schema.ResourceData, v interface{}) error {
@@ -1031,6 +1109,11 @@ func resourceControllerCreate(r *schema.ResourceData, v interface{}) error {
 		return err
 	}
 
+	err = writeLastApplied(client.RESTClient, item)
+	if err != nil {
+		return err
+	}
+
 	item, err = client.ReplicationControllers(namespace).Create(item)
 	if err != nil {
 		return err
@@ -1042,9 +1125,22 @@ func resourceControllerCreate(r *schema.ResourceData, v interface{}) error {
 
 func resourceControllerUpdate(r *schema.ResourceData, v interface{}) error {
 	client := extractClient(v)
-	namespace, name := split(r.Id())
+	namespace, name, err := split(r.Id())
+	if err != nil {
+		return err
+	}
 	rcs := client.ReplicationControllers(namespace)
 
+	rollout, err := readRolloutConfig(r)
+	if err != nil {
+		return err
+	}
+
+	err = recoverControllerRollout(client, namespace, name, rollout)
+	if err != nil {
+		return err
+	}
+
 	item, err := rcs.Get(name)
 	if err != nil {
 		return err
@@ -1066,18 +1162,43 @@ func resourceControllerUpdate(r *schema.ResourceData, v interface{}) error {
 		}
 		delete(item.ObjectMeta.Annotations, "kubectl.kubernetes.io/original-replicas")
 	}
-	if originalReplicas < 0 {
+	if originalReplicas < 0 {
+		originalReplicas = item.Spec.Replicas
+	}
+
+	// when an autoscaler owns the replica count the current count is kept,
+	// both in place and for the replacement of a rollout
+	initialOnly := r.Get("replicas_initial_only").(bool)
+	if initialOnly {
 		originalReplicas = item.Spec.Replicas
 	}
 
-	if !r.HasChange("template") {
-		// inplace update
-		err := writeReplicationController(r, item, originalDeployment, -1)
-		if err != nil {
-			return err
+	changed, err := controllerTemplateChanged(r, v)
+	if err != nil {
+		return err
+	}
+
+	if !changed {
+		// inplace update; with an autoscaler both objects get the current
+		// replica count, so it is left out of the patch
+		replicas := -1
+		if initialOnly {
+			replicas = item.Spec.Replicas
 		}
+		err = patchObject(client.RESTClient, "replicationcontrollers", r, v, func(r *schema.ResourceData) (runtime.Object, error) {
+			item := &api.ReplicationController{}
+			item.Namespace = namespace
+			item.Name = name
 
-		_, err = rcs.Update(item)
+			err := writeReplicationController(r, item, originalDeployment, replicas)
+			if err != nil {
+				return nil, err
+			}
+			if initialOnly {
+				item.ObjectMeta.Annotations["kubectl.kubernetes.io/original-replicas"] = strconv.Itoa(replicas)
+			}
+			return item, nil
+		}, &api.ReplicationController{})
 		if err != nil {
 			return err
 		}
@@ -1087,15 +1208,32 @@ func resourceControllerUpdate(r *schema.ResourceData, v interface{}) error {
 
 	deployment := uuid.New()
 	tmpRcName := name + "-" + deployment
+	live := item
 
 	{ // create tmp RC
 		item := &api.ReplicationController{}
-		item.Name = tmpRcName
+		item.Name = name
+
+		err := writeReplicationController(r, item, deployment, -1)
+		if err != nil {
+			return err
+		}
 
-		err := writeReplicationController(r, item, deployment, 0)
+		// the replacement takes over the name and the configuration of the
+		// original once it is promoted
+		err = writeLastApplied(client.RESTClient, item)
 		if err != nil {
 			return err
 		}
+		keepForeignMetadata(&item.ObjectMeta, &live.ObjectMeta)
+		item.Name = tmpRcName
+		item.Spec.Replicas = 0
+		if initialOnly {
+			item.ObjectMeta.Annotations["kubectl.kubernetes.io/original-replicas"] = strconv.Itoa(originalReplicas)
+		}
+		item.ObjectMeta.Annotations[rolloutOfAnnotation] = name
+		item.ObjectMeta.Annotations[rolloutPhaseAnnotation] = rolloutPhaseScaling
+		item.ObjectMeta.Annotations[rolloutLeaseAnnotation] = rolloutLease(rollout)
 
 		item, err = rcs.Create(item)
 		if err != nil {
@@ -1113,9 +1251,12 @@ func resourceControllerUpdate(r *schema.ResourceData, v interface{}) error {
 	)
 	if x := r.Get("replicas"); x != nil {
 		replacementTarget = x.(int)
-		if replacementStep > replacementTarget {
-			replacementStep = replacementTarget
-		}
+	}
+	if initialOnly {
+		replacementTarget = originalReplicas
+	}
+	if replacementStep > replacementTarget {
+		replacementStep = replacementTarget
 	}
 
 	done := false
@@ -1125,6 +1266,11 @@ func resourceControllerUpdate(r *schema.ResourceData, v interface{}) error {
 			replacement *api.ReplicationController
 		)
 
+		err = renewRolloutLease(rcs, tmpRcName, rollout)
+		if err != nil {
+			return err
+		}
+
 		original, err = rcs.Get(name)
 		if err != nil {
 			return err
@@ -1134,10 +1280,17 @@ func resourceControllerUpdate(r *schema.ResourceData, v interface{}) error {
 			return err
 		}
 
-		cond := crossScaled(client, original, replacement, 30*time.Second)
-		err := wait.Poll(1*time.Second, 2*time.Minute, cond)
+		cond := crossScaled(client, original, replacement, rollout.SettleDuration)
+		err := wait.Poll(1*time.Second, rollout.StepTimeout, cond)
 		if err != nil {
-			return err
+			if !rollout.RollbackOnFailure {
+				return fmt.Errorf("rollout of %s failed, %s was left in place: %s", name, tmpRcName, err)
+			}
+			rollbackErr := rollbackController(client, namespace, name, tmpRcName, originalReplicas, rollout)
+			if rollbackErr != nil {
+				return fmt.Errorf("rollout of %s failed: %s (rollback failed: %s)", name, err, rollbackErr)
+			}
+			return fmt.Errorf("rollout of %s failed and was rolled back: %s", name, err)
 		}
 
 		{ // are we done
@@ -1157,17 +1310,17 @@ func resourceControllerUpdate(r *schema.ResourceData, v interface{}) error {
 
 		if scaleReplacement {
 			scaleReplacement = !scaleReplacement
-			if replacementStep < replacementTarget {
-				replacementStep++
-
-				item, err := rcs.Get(tmpRcName)
-				if err != nil {
-					return err
-				}
-
-				item.Spec.Replicas = replacementStep
+			step := rollout.StepSize
+			if x := replacementTarget - replacementStep; x < step {
+				step = x
+			}
+			if x := replacementTarget + rollout.MaxSurge - originalStep - replacementStep; x < step {
+				step = x
+			}
+			if step > 0 {
+				replacementStep += step
 
-				_, err = rcs.Update(item)
+				_, err := scaleController(rcs, tmpRcName, replacementStep)
 				if err != nil {
 					return err
 				}
@@ -1175,69 +1328,452 @@ func resourceControllerUpdate(r *schema.ResourceData, v interface{}) error {
 		} else {
 			scaleReplacement = !scaleReplacement
 			if originalStep > 0 {
-				originalStep--
+				originalStep -= rollout.StepSize
+				if originalStep < 0 {
+					originalStep = 0
+				}
+			}
 
-				item, err := rcs.Get(name)
+			// an autoscaler may still be resizing the original, so its
+			// count is enforced on every step
+			if original.Spec.Replicas != originalStep {
+				_, err := scaleController(rcs, name, originalStep)
 				if err != nil {
 					return err
 				}
+			}
+		}
+	}
 
-				item.Spec.Replicas = originalStep
+	replacement, err := rcs.Get(tmpRcName)
+	if err != nil {
+		return err
+	}
 
-				_, err = rcs.Update(item)
-				if err != nil {
-					return err
-				}
+	return promoteController(rcs, name, replacement)
+}
+
+// controllerTemplateChanged reports whether the pod template of r differs
+// from the one of the previous state. When Create adopts an existing RC, r
+// has no state and the previous state is read from the object, so the values
+// the server defaults are only compared when they are configured.
+func controllerTemplateChanged(r *schema.ResourceData, v interface{}) (bool, error) {
+	previous := extractPrevious(v)
+	if previous == nil {
+		return r.HasChange("template"), nil
+	}
+
+	current := api.PodTemplateSpec{}
+	if err := writePodTemplateSpec(previous, &current); err != nil {
+		// the object has no template
+		return true, nil
+	}
+	desired := api.PodTemplateSpec{}
+	if err := writePodTemplateSpec(r, &desired); err != nil {
+		return false, err
+	}
+
+	if desired.Spec.RestartPolicy == "" {
+		desired.Spec.RestartPolicy = current.Spec.RestartPolicy
+	}
+	if desired.Spec.DNSPolicy == "" {
+		desired.Spec.DNSPolicy = current.Spec.DNSPolicy
+	}
+	if desired.Spec.TerminationGracePeriodSeconds == nil {
+		desired.Spec.TerminationGracePeriodSeconds = current.Spec.TerminationGracePeriodSeconds
+	}
+
+	return !api.Semantic.DeepEqual(current, desired), nil
+}
+
+const (
+	rolloutOfAnnotation    = "terraform.io/rollout-of"
+	rolloutPhaseAnnotation = "terraform.io/rollout-phase"
+	rolloutLeaseAnnotation = "terraform.io/rollout-lease-expires"
+
+	rolloutPhaseScaling  = "scaling"
+	rolloutPhaseSwapping = "swapping"
+)
+
+// promoteController replaces the original RC with a copy of the temporary RC
+// of a rollout. Every step is safe to repeat, so a swap that was interrupted
+// can be completed by calling it again.
+func promoteController(
+	rcs unversioned.ReplicationControllerInterface,
+	name string,
+	tmp *api.ReplicationController,
+) error {
+	var err error
+
+	if tmp.ObjectMeta.Annotations[rolloutPhaseAnnotation] != rolloutPhaseSwapping {
+		tmpName := tmp.Name
+		err = retryUpdate(func() error {
+			latest, err := rcs.Get(tmpName)
+			if err != nil {
+				return err
+			}
+
+			latest.ObjectMeta.Annotations[rolloutPhaseAnnotation] = rolloutPhaseSwapping
+			latest, err = rcs.Update(latest)
+			if err != nil {
+				return err
 			}
+
+			tmp = latest
+			return nil
+		})
+		if err != nil {
+			return err
 		}
 	}
 
-	{ // delete original RC
-		err := rcs.Delete(name)
+	deployment := tmp.Spec.Template.ObjectMeta.Labels["deployment"]
+
+	original, err := rcs.Get(name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	exists := err == nil
+	recreated := exists &&
+		original.Spec.Template != nil &&
+		original.Spec.Template.ObjectMeta.Labels["deployment"] == deployment
+
+	if !recreated {
+		if exists {
+			err = rcs.Delete(name)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+
+		item := &api.ReplicationController{}
+		item.Name = name
+		item.Namespace = tmp.Namespace
+		item.Labels = tmp.Labels
+		item.Annotations = map[string]string{}
+		for k, v := range tmp.Annotations {
+			if k == rolloutOfAnnotation || k == rolloutPhaseAnnotation || k == rolloutLeaseAnnotation {
+				continue
+			}
+			item.Annotations[k] = v
+		}
+		item.Spec = tmp.Spec
+		if exists {
+			// labels and annotations set on the original since the
+			// rollout started
+			keepForeignMetadata(&item.ObjectMeta, &original.ObjectMeta)
+		}
+
+		_, err = rcs.Create(item)
 		if err != nil {
 			return err
 		}
 	}
 
-	{ // create new RC
+	err = rcs.Delete(tmp.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// rolloutLease returns the time until which a rollout is considered to be in
+// progress. The rollout renews it on every step, a rollout that missed two
+// steps was interrupted.
+func rolloutLease(rollout rolloutConfig) string {
+	return time.Now().Add(2 * rollout.StepTimeout).UTC().Format(time.RFC3339)
+}
+
+func renewRolloutLease(
+	rcs unversioned.ReplicationControllerInterface,
+	tmpRcName string,
+	rollout rolloutConfig,
+) error {
+	return retryUpdate(func() error {
 		item, err := rcs.Get(tmpRcName)
 		if err != nil {
 			return err
 		}
 
-		item.Name = name
-		item.ResourceVersion = ""
+		item.ObjectMeta.Annotations[rolloutLeaseAnnotation] = rolloutLease(rollout)
+		_, err = rcs.Update(item)
+		return err
+	})
+}
 
-		_, err = rcs.Create(item)
+// rolloutLeaseExpired reports whether the rollout that created tmp stopped
+// renewing its lease. Rollouts started by older versions have no lease.
+func rolloutLeaseExpired(tmp *api.ReplicationController) bool {
+	x, ok := tmp.ObjectMeta.Annotations[rolloutLeaseAnnotation]
+	if !ok {
+		return true
+	}
+	expires, err := time.Parse(time.RFC3339, x)
+	if err != nil {
+		return true
+	}
+	return time.Now().After(expires)
+}
+
+// findControllerRollout returns the temporary RC of a rollout of the named
+// RC, or nil if there is none.
+func findControllerRollout(
+	rcs unversioned.ReplicationControllerInterface,
+	name string,
+) (*api.ReplicationController, error) {
+	list, err := rcs.List(api.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range list.Items {
+		if list.Items[i].ObjectMeta.Annotations[rolloutOfAnnotation] == name {
+			return &list.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// recoverControllerRollout completes or reverts any rollout of the named RC
+// that was interrupted, as recorded in the annotations of its temporary RC.
+// Rollouts that had started swapping the RCs are completed, rollouts that were
+// still scaling are rolled back. A rollout whose lease has not expired is
+// still running in another apply and is left alone.
+func recoverControllerRollout(
+	client *unversioned.Client,
+	namespace, name string,
+	rollout rolloutConfig,
+) error {
+	rcs := client.ReplicationControllers(namespace)
+
+	list, err := rcs.List(api.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for i := range list.Items {
+		tmp := &list.Items[i]
+		if tmp.ObjectMeta.Annotations[rolloutOfAnnotation] != name {
+			continue
+		}
+
+		if !rolloutLeaseExpired(tmp) {
+			return fmt.Errorf("%s is being rolled out to %s by another apply until %s",
+				name, tmp.Name, tmp.ObjectMeta.Annotations[rolloutLeaseAnnotation])
+		}
+
+		if tmp.ObjectMeta.Annotations[rolloutPhaseAnnotation] == rolloutPhaseSwapping {
+			err = promoteController(rcs, name, tmp)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		original, err := rcs.Get(name)
+		if errors.IsNotFound(err) {
+			err = promoteController(rcs, name, tmp)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		replicas := original.Spec.Replicas
+		if x, ok := original.ObjectMeta.Annotations["kubectl.kubernetes.io/original-replicas"]; ok {
+			replicas, err = strconv.Atoi(x)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = rollbackController(client, namespace, name, tmp.Name, replicas, rollout)
 		if err != nil {
 			return err
 		}
 	}
 
-	{ // delete tmp RC
-		err := rcs.Delete(tmpRcName)
+	return nil
+}
+
+type rolloutConfig struct {
+	SettleDuration    time.Duration
+	StepTimeout       time.Duration
+	StepSize          int
+	MaxSurge          int
+	RollbackOnFailure bool
+}
+
+func readRolloutConfig(r *schema.ResourceData) (rolloutConfig, error) {
+	cfg := rolloutConfig{
+		SettleDuration: 30 * time.Second,
+		StepTimeout:    2 * time.Minute,
+		StepSize:       1,
+		MaxSurge:       1,
+	}
+
+	m, ok := extractSingleMap(r.Get("rollout"))
+	if !ok {
+		return cfg, nil
+	}
+
+	if x, ok := m["settle_duration"].(string); ok && x != "" {
+		d, err := time.ParseDuration(x)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.SettleDuration = d
+	}
+	if x, ok := m["step_timeout"].(string); ok && x != "" {
+		d, err := time.ParseDuration(x)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.StepTimeout = d
+	}
+	if x, ok := m["step_size"].(int); ok && x > 0 {
+		cfg.StepSize = x
+	}
+	if x, ok := m["max_surge"].(int); ok && x >= 0 {
+		cfg.MaxSurge = x
+	}
+	if x, ok := m["rollback_on_failure"].(bool); ok {
+		cfg.RollbackOnFailure = x
+	}
+
+	return cfg, nil
+}
+
+// scaleController sets the replica count of an RC. The replication manager
+// updates the status of an RC constantly, so conflicts are retried.
+func scaleController(
+	rcs unversioned.ReplicationControllerInterface,
+	name string,
+	replicas int,
+) (*api.ReplicationController, error) {
+	var item *api.ReplicationController
+	err := retryUpdate(func() (err error) {
+		item, err = rcs.Get(name)
 		if err != nil {
 			return err
 		}
+
+		item.Spec.Replicas = replicas
+		item, err = rcs.Update(item)
+		return err
+	})
+	return item, err
+}
+
+// rollbackController scales the original RC back to its previous size and
+// removes the temporary RC of a failed rollout.
+func rollbackController(
+	client *unversioned.Client,
+	namespace, name, tmpRcName string,
+	replicas int,
+	rollout rolloutConfig,
+) error {
+	rcs := client.ReplicationControllers(namespace)
+
+	original, err := scaleController(rcs, name, replicas)
+	if err != nil {
+		return err
+	}
+
+	err = wait.Poll(1*time.Second, rollout.StepTimeout, scaled(client, original, 0))
+	if err != nil {
+		return err
+	}
+
+	replacement, err := scaleController(rcs, tmpRcName, 0)
+	if errors.IsNotFound(err) {
+		// the temporary RC was already removed
+		return nil
+	}
+	if err != nil {
+		return err
 	}
 
+	err = wait.Poll(1*time.Second, rollout.StepTimeout, scaled(client, replacement, 0))
+	if err != nil {
+		return err
+	}
+
+	err = rcs.Delete(tmpRcName)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
 	return nil
 }
 
 func resourceControllerDelete(r *schema.ResourceData, v interface{}) error {
 	client := extractClient(v)
-	namespace, name := split(r.Id())
+	namespace, name, err := split(r.Id())
+	if err != nil {
+		return err
+	}
+	rcs := client.ReplicationControllers(namespace)
+
+	timeout, err := readDuration(r, "delete_timeout", 2*time.Minute)
+	if err != nil {
+		return err
+	}
+
+	rollout, err := readRolloutConfig(r)
+	if err != nil {
+		return err
+	}
+
+	// the RC may only exist as the replacement of an interrupted rollout
+	err = recoverControllerRollout(client, namespace, name, rollout)
+	if err != nil {
+		return err
+	}
+
+	if !r.Get("orphan_pods").(bool) {
+		// Deleting an RC leaves its pods running, so scale down to zero and
+		// wait for the pods to go away before removing the RC itself.
+		item, err := scaleController(rcs, name, 0)
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = wait.Poll(1*time.Second, timeout, scaled(client, item, 0))
+		if err != nil {
+			return err
+		}
+	}
 
-	return client.ReplicationControllers(namespace).Delete(name)
+	err = rcs.Delete(name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
 }
 
 func resourceControllerExists(r *schema.ResourceData, v interface{}) (bool, error) {
 	client := extractClient(v)
-	namespace, name := split(r.Id())
+	namespace, name, err := split(r.Id())
+	if err != nil {
+		return false, err
+	}
+
+	rcs := client.ReplicationControllers(namespace)
 
-	_, err := client.ReplicationControllers(namespace).Get(name)
+	_, err = rcs.Get(name)
 	if errors.IsNotFound(err) {
-		return false, nil
+		// the RC still exists when an interrupted rollout left its
+		// replacement behind; the next Update or Delete completes the swap.
+		tmp, err := findControllerRollout(rcs, name)
+		return tmp != nil, err
 	}
 	if err != nil {
 		return false, err
@@ -1284,6 +1820,24 @@ func writeReplicationController(
 	return nil
 }
 
+func validateControllerConfig(r *schema.ResourceData) field.ErrorList {
+	item := &api.ReplicationController{}
+	item.Namespace = r.Get("namespace").(string)
+	item.Name = r.Get("name").(string)
+
+	err := writeReplicationController(r, item, "validate", -1)
+	if err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "template"), "", err.Error())}
+	}
+
+	err = applyDefaults(item, "v1")
+	if err != nil {
+		return field.ErrorList{field.InternalError(field.NewPath("spec"), err)}
+	}
+
+	return validation.ValidateReplicationController(item)
+}
+
 func writePodTemplateSpec(r *schema.ResourceData, item *api.PodTemplateSpec) error {
 	var template map[string]interface{}
 	if x, ok := extractSingleMap(r.Get("template")); ok {
@@ -1369,12 +1923,7 @@ func writePodVolume(m map[string]interface{}, item *api.Volume) {
 		item.Name = x
 	}
 
-	if n, ok := extractSingleMap(m["host_path"]); ok {
-		item.HostPath = &api.HostPathVolumeSource{}
-		if x, ok := n["path"].(string); ok {
-			item.HostPath.Path = x
-		}
-	}
+	item.HostPath = writeHostPathVolumeSource(m["host_path"])
 
 	if n, ok := extractSingleMap(m["empty_dir"]); ok {
 		item.EmptyDir = &api.EmptyDirVolumeSource{}
@@ -1383,37 +1932,8 @@ func writePodVolume(m map[string]interface{}, item *api.Volume) {
 		}
 	}
 
-	if n, ok := extractSingleMap(m["gce_persistent_disk"]); ok {
-		item.GCEPersistentDisk = &api.GCEPersistentDiskVolumeSource{}
-		if x, ok := n["pd_name"].(string); ok {
-			item.GCEPersistentDisk.PDName = x
-		}
-		if x, ok := n["fs_type"].(string); ok {
-			item.GCEPersistentDisk.FSType = x
-		}
-		if x, ok := n["partition"].(int); ok {
-			item.GCEPersistentDisk.Partition = x
-		}
-		if x, ok := n["read_only"].(bool); ok {
-			item.GCEPersistentDisk.ReadOnly = x
-		}
-	}
-
-	if n, ok := extractSingleMap(m["aws_elastic_block_store"]); ok {
-		item.AWSElasticBlockStore = &api.AWSElasticBlockStoreVolumeSource{}
-		if x, ok := n["volume_id"].(string); ok {
-			item.AWSElasticBlockStore.VolumeID = x
-		}
-		if x, ok := n["fs_type"].(string); ok {
-			item.AWSElasticBlockStore.FSType = x
-		}
-		if x, ok := n["partition"].(int); ok {
-			item.AWSElasticBlockStore.Partition = x
-		}
-		if x, ok := n["read_only"].(bool); ok {
-			item.AWSElasticBlockStore.ReadOnly = x
-		}
-	}
+	item.GCEPersistentDisk = writeGCEPersistentDiskVolumeSource(m["gce_persistent_disk"])
+	item.AWSElasticBlockStore = writeAWSElasticBlockStoreVolumeSource(m["aws_elastic_block_store"])
 
 	if n, ok := extractSingleMap(m["git_repo"]); ok {
 		item.GitRepo = &api.GitRepoVolumeSource{}
@@ -1435,20 +1955,19 @@ func writePodVolume(m map[string]interface{}, item *api.Volume) {
 		}
 	}
 
-	// TODO:
-	// NFS *NFSVolumeSource
-	// ISCSI *ISCSIVolumeSource
-	// Glusterfs *GlusterfsVolumeSource
-	// PersistentVolumeClaim *PersistentVolumeClaimVolumeSource
-	// RBD *RBDVolumeSource
-	// FlexVolume *FlexVolumeSource
-	// Cinder *CinderVolumeSource
-	// CephFS *CephFSVolumeSource
-	// Flocker *FlockerVolumeSource
-	// DownwardAPI *DownwardAPIVolumeSource
-	// FC *FCVolumeSource
-	// AzureFile *AzureFileVolumeSource
-	// ConfigMap *ConfigMapVolumeSource
+	item.PersistentVolumeClaim = writePersistentVolumeClaimVolumeSource(m["persistent_volume_claim"])
+	item.NFS = writeNFSVolumeSource(m["nfs"])
+	item.ISCSI = writeISCSIVolumeSource(m["iscsi"])
+	item.Glusterfs = writeGlusterfsVolumeSource(m["glusterfs"])
+	item.RBD = writeRBDVolumeSource(m["rbd"])
+	item.FlexVolume = writeFlexVolumeSource(m["flex_volume"])
+	item.Cinder = writeCinderVolumeSource(m["cinder"])
+	item.CephFS = writeCephFSVolumeSource(m["ceph_fs"])
+	item.Flocker = writeFlockerVolumeSource(m["flocker"])
+	item.DownwardAPI = writeDownwardAPIVolumeSource(m["downward_api"])
+	item.FC = writeFCVolumeSource(m["fc"])
+	item.AzureFile = writeAzureFileVolumeSource(m["azure_file"])
+	item.ConfigMap = writeConfigMapVolumeSource(m["config_map"])
 }
 
 func writePodImagePullSecret(m map[string]interface{}, item *api.LocalObjectReference) {
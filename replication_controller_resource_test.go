@@ -0,0 +1,465 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+
+	"k8s.io/kubernetes/pkg/api"
+)
+
+func TestReadRolloutConfig(t *testing.T) {
+	defaults := rolloutConfig{
+		SettleDuration: 30 * time.Second,
+		StepTimeout:    2 * time.Minute,
+		StepSize:       1,
+		MaxSurge:       1,
+	}
+
+	cases := []struct {
+		Attributes map[string]string
+		Expected   rolloutConfig
+		Err        bool
+	}{
+		{
+			Attributes: map[string]string{},
+			Expected:   defaults,
+		},
+		{
+			Attributes: map[string]string{
+				"rollout.#":                     "1",
+				"rollout.0.settle_duration":     "5s",
+				"rollout.0.step_timeout":        "10m",
+				"rollout.0.step_size":           "3",
+				"rollout.0.max_surge":           "0",
+				"rollout.0.rollback_on_failure": "true",
+			},
+			Expected: rolloutConfig{
+				SettleDuration:    5 * time.Second,
+				StepTimeout:       10 * time.Minute,
+				StepSize:          3,
+				MaxSurge:          0,
+				RollbackOnFailure: true,
+			},
+		},
+		// a step size below one keeps the default
+		{
+			Attributes: map[string]string{
+				"rollout.#":           "1",
+				"rollout.0.step_size": "0",
+				"rollout.0.max_surge": "1",
+			},
+			Expected: defaults,
+		},
+		{
+			Attributes: map[string]string{
+				"rollout.#":                 "1",
+				"rollout.0.settle_duration": "soon",
+			},
+			Err: true,
+		},
+	}
+
+	res := replicationControllerResource()
+	for i, tc := range cases {
+		state := &terraform.InstanceState{ID: "default/web", Attributes: tc.Attributes}
+		v, err := withPrevious(res.Schema, state, &providerMeta{})
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		actual, err := readRolloutConfig(extractPrevious(v))
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: err: %v", i, err)
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestRolloutLeaseExpired(t *testing.T) {
+	cases := []struct {
+		Annotations map[string]string
+		Expected    bool
+	}{
+		{
+			Annotations: map[string]string{
+				rolloutLeaseAnnotation: time.Now().Add(time.Minute).UTC().Format(time.RFC3339),
+			},
+			Expected: false,
+		},
+		{
+			Annotations: map[string]string{
+				rolloutLeaseAnnotation: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+			},
+			Expected: true,
+		},
+		{
+			Annotations: map[string]string{
+				rolloutLeaseAnnotation: rolloutLease(rolloutConfig{StepTimeout: time.Minute}),
+			},
+			Expected: false,
+		},
+		// rollouts of older versions have no lease
+		{
+			Annotations: map[string]string{
+				rolloutOfAnnotation: "web",
+			},
+			Expected: true,
+		},
+		{
+			Annotations: map[string]string{
+				rolloutLeaseAnnotation: "tomorrow",
+			},
+			Expected: true,
+		},
+	}
+
+	for i, tc := range cases {
+		tmp := &api.ReplicationController{}
+		tmp.ObjectMeta.Annotations = tc.Annotations
+		actual := rolloutLeaseExpired(tmp)
+		if actual != tc.Expected {
+			t.Fatalf("%d: bad: %v", i, actual)
+		}
+	}
+}
+
+// controllerManager is a react hook for the fake server that keeps the pods
+// of the replication controllers at their replica count. The pods are ready
+// as soon as they are created.
+func controllerManager(s *fakeServer, p string, obj map[string]interface{}) {
+	if path.Base(path.Dir(p)) != "replicationcontrollers" {
+		return
+	}
+	pods := path.Join(path.Dir(path.Dir(p)), "pods")
+	name := path.Base(p)
+
+	var owned []string
+	for k, m := range s.objects {
+		if path.Dir(k) != pods {
+			continue
+		}
+		meta := m["metadata"].(map[string]interface{})
+		if meta["annotations"].(map[string]interface{})["test/rc"] == name {
+			owned = append(owned, k)
+		}
+	}
+
+	replicas := 0.0
+	if obj != nil {
+		spec := obj["spec"].(map[string]interface{})
+		replicas, _ = spec["replicas"].(float64)
+		meta := obj["metadata"].(map[string]interface{})
+		obj["status"] = map[string]interface{}{
+			"replicas":           replicas,
+			"observedGeneration": meta["generation"],
+		}
+	}
+
+	for len(owned) > int(replicas) {
+		delete(s.objects, owned[0])
+		owned = owned[1:]
+	}
+	for i := 0; len(owned) < int(replicas); i++ {
+		k := path.Join(pods, fmt.Sprintf("%s-%d", name, i))
+		if _, ok := s.objects[k]; ok {
+			continue
+		}
+
+		tmpl := copyJSON(obj["spec"].(map[string]interface{})["template"].(map[string]interface{}))
+		meta := tmpl["metadata"].(map[string]interface{})
+		meta["annotations"] = map[string]interface{}{"test/rc": name}
+		spec := tmpl["spec"].(map[string]interface{})
+
+		var statuses []interface{}
+		for _, c := range spec["containers"].([]interface{}) {
+			statuses = append(statuses, map[string]interface{}{
+				"name":  c.(map[string]interface{})["name"],
+				"ready": true,
+				"state": map[string]interface{}{
+					"running": map[string]interface{}{"startedAt": "2000-01-01T00:00:00Z"},
+				},
+			})
+		}
+
+		s.store(k, map[string]interface{}{
+			"kind":       "Pod",
+			"apiVersion": "v1",
+			"metadata":   meta,
+			"spec":       spec,
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "True"},
+				},
+				"containerStatuses": statuses,
+			},
+		})
+		owned = append(owned, k)
+	}
+}
+
+func controllerConfig(image string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":     "web",
+		"replicas": 2,
+		"labels":   map[string]interface{}{"app": "web"},
+		"rollout": []interface{}{
+			map[string]interface{}{"settle_duration": "0s", "step_size": 2},
+		},
+		"template": []interface{}{
+			map[string]interface{}{
+				"labels": map[string]interface{}{"app": "web"},
+				"container": []interface{}{
+					map[string]interface{}{"name": "web", "image": image, "image_pull_policy": "IfNotPresent"},
+				},
+			},
+		},
+	}
+}
+
+// TestControllerRolloutKeepsForeignMetadata rolls out a new template of an
+// RC that another tool has labeled and annotated.
+func TestControllerRolloutKeepsForeignMetadata(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+	s.react = controllerManager
+
+	res := providerResource(replicationControllerResource())
+	state, err := apply(t, res, nil, controllerConfig("web:1"), s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	const p = "/api/v1/namespaces/default/replicationcontrollers/web"
+	item := &api.ReplicationController{}
+	s.get(p, item)
+	item.Labels["team"] = "platform"
+	item.Annotations["example.com/checked"] = "true"
+	s.put(p, item)
+
+	state, err = res.Refresh(state, s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	_, err = apply(t, res, state, controllerConfig("web:2"), s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	item = &api.ReplicationController{}
+	if !s.get(p, item) {
+		t.Fatalf("%s is gone", p)
+	}
+	if x := item.Spec.Template.Spec.Containers[0].Image; x != "web:2" {
+		t.Fatalf("bad image: %s", x)
+	}
+	if item.Labels["team"] != "platform" || item.Labels["app"] != "web" {
+		t.Fatalf("bad labels: %v", item.Labels)
+	}
+	if item.Annotations["example.com/checked"] != "true" {
+		t.Fatalf("bad annotations: %v", item.Annotations)
+	}
+	for k := range item.Annotations {
+		if strings.HasPrefix(k, "terraform.io/rollout-") {
+			t.Fatalf("bad annotations: %v", item.Annotations)
+		}
+	}
+	if l := s.list("/api/v1/namespaces/default/replicationcontrollers"); len(l) != 1 {
+		t.Fatalf("bad controllers: %v", l)
+	}
+}
+
+// TestControllerAdoption adopts an RC that was created outside Terraform.
+// Only a different template starts a rollout, which replaces the deployment
+// label of the pods.
+func TestControllerAdoption(t *testing.T) {
+	cases := []struct {
+		Image   string
+		Rollout bool
+	}{
+		{Image: "web:1", Rollout: false},
+		{Image: "web:2", Rollout: true},
+	}
+
+	const p = "/api/v1/namespaces/default/replicationcontrollers/web"
+	for i, tc := range cases {
+		s := newFakeServer(t)
+		s.react = controllerManager
+
+		// the server defaults the policies and the grace period
+		grace := int64(30)
+		item := &api.ReplicationController{}
+		item.Labels = map[string]string{"app": "web"}
+		item.Spec.Replicas = 2
+		item.Spec.Selector = map[string]string{"app": "web", "deployment": "kubectl"}
+		item.Spec.Template = &api.PodTemplateSpec{}
+		item.Spec.Template.Labels = item.Spec.Selector
+		item.Spec.Template.Spec.RestartPolicy = api.RestartPolicyAlways
+		item.Spec.Template.Spec.DNSPolicy = api.DNSClusterFirst
+		item.Spec.Template.Spec.TerminationGracePeriodSeconds = &grace
+		item.Spec.Template.Spec.Containers = []api.Container{{Name: "web", Image: "web:1"}}
+		s.put(p, item)
+
+		raw := controllerConfig(tc.Image)
+		raw["adopt_existing"] = true
+		res := providerResource(replicationControllerResource())
+		_, err := apply(t, res, nil, raw, s.meta())
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		item = &api.ReplicationController{}
+		s.get(p, item)
+		s.Close()
+
+		if x := item.Spec.Template.Spec.Containers[0].Image; x != tc.Image {
+			t.Fatalf("%d: bad image: %s", i, x)
+		}
+		if x := item.Annotations[ownedAnnotation]; x != defaultOwner {
+			t.Fatalf("%d: bad owner: %q", i, x)
+		}
+		rollout := item.Spec.Template.Labels["deployment"] != "kubectl"
+		if rollout != tc.Rollout {
+			t.Fatalf("%d: bad rollout: %v", i, rollout)
+		}
+	}
+}
+
+// TestRollbackController rolls back a rollout, also when its temporary RC is
+// already gone.
+func TestRollbackController(t *testing.T) {
+	const rcs = "/api/v1/namespaces/default/replicationcontrollers"
+
+	for i, exists := range []bool{true, false} {
+		s := newFakeServer(t)
+		s.react = controllerManager
+
+		for _, name := range []string{"web", "web-tmp"} {
+			if name == "web-tmp" && !exists {
+				continue
+			}
+			item := &api.ReplicationController{}
+			item.Spec.Selector = map[string]string{"app": "web", "deployment": name}
+			item.Spec.Template = &api.PodTemplateSpec{}
+			item.Spec.Template.Labels = item.Spec.Selector
+			item.Spec.Template.Spec.Containers = []api.Container{{Name: "web", Image: "web:1"}}
+			if name == "web-tmp" {
+				item.Spec.Replicas = 2
+			}
+			s.put(path.Join(rcs, name), item)
+		}
+
+		err := rollbackController(extractClient(s.meta()), "default", "web", "web-tmp", 2, rolloutConfig{StepTimeout: 10 * time.Second})
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		item := &api.ReplicationController{}
+		s.get(path.Join(rcs, "web"), item)
+		l := s.list(rcs)
+		s.Close()
+
+		if item.Spec.Replicas != 2 {
+			t.Fatalf("%d: bad replicas: %d", i, item.Spec.Replicas)
+		}
+		if len(l) != 1 {
+			t.Fatalf("%d: bad controllers: %v", i, l)
+		}
+	}
+}
+
+// TestControllerDeleteGone deletes an RC that was already removed.
+func TestControllerDeleteGone(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+
+	res := replicationControllerResource()
+	for _, orphan := range []string{"false", "true"} {
+		r := resourceData(t, res, "default/web", map[string]string{
+			"namespace":   "default",
+			"name":        "web",
+			"orphan_pods": orphan,
+		})
+		err := res.Delete(r, s.meta())
+		if err != nil {
+			t.Fatalf("orphan_pods %s: err: %s", orphan, err)
+		}
+	}
+}
+
+// TestControllerReplicasInitialOnly lets an autoscaler scale an RC with
+// replicas_initial_only. Neither an update in place nor a rollout resets
+// the replica count.
+func TestControllerReplicasInitialOnly(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+	s.react = controllerManager
+
+	config := func(image, team string) map[string]interface{} {
+		raw := controllerConfig(image)
+		raw["replicas_initial_only"] = true
+		raw["labels"] = map[string]interface{}{"app": "web", "team": team}
+		return raw
+	}
+
+	res := providerResource(replicationControllerResource())
+	state, err := apply(t, res, nil, config("web:1", "a"), s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	const p = "/api/v1/namespaces/default/replicationcontrollers/web"
+	scale := func(replicas int) {
+		item := &api.ReplicationController{}
+		s.get(p, item)
+		item.Spec.Replicas = replicas
+		s.put(p, item)
+	}
+	check := func(step string, replicas int) {
+		item := &api.ReplicationController{}
+		if !s.get(p, item) {
+			t.Fatalf("%s: %s is gone", step, p)
+		}
+		if item.Spec.Replicas != replicas {
+			t.Fatalf("%s: bad replicas: %d", step, item.Spec.Replicas)
+		}
+	}
+
+	scale(4)
+	state, err = res.Refresh(state, s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err = apply(t, res, state, config("web:1", "b"), s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	check("in place", 4)
+
+	scale(3)
+	state, err = res.Refresh(state, s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	_, err = apply(t, res, state, config("web:2", "b"), s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	check("rollout", 3)
+
+	item := &api.ReplicationController{}
+	s.get(p, item)
+	if x := item.Spec.Template.Spec.Containers[0].Image; x != "web:2" {
+		t.Fatalf("bad image: %s", x)
+	}
+}
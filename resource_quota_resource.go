@@ -0,0 +1,190 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/validation"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+)
+
+func resourceQuotaResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "default",
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"annotations": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"hard": {
+				Type:         schema.TypeMap,
+				Required:     true,
+				ValidateFunc: validateQuantityMap,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+
+			"used": {
+				Type:     schema.TypeMap,
+				Computed: true,
+			},
+		},
+		Create: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace := r.Get("namespace").(string)
+			name := r.Get("name").(string)
+
+			item := &api.ResourceQuota{}
+			item.Name = name
+
+			err := writeResourceQuota(r, item)
+			if err != nil {
+				return err
+			}
+
+			err = writeLastApplied(client.RESTClient, item)
+			if err != nil {
+				return err
+			}
+
+			item, err = client.ResourceQuotas(namespace).Create(item)
+			if err != nil {
+				return err
+			}
+
+			r.SetId(join(namespace, name))
+			r.Set("used", readResourceList(item.Status.Used, r.Get("used")))
+			return nil
+		},
+		Read: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			item, err := client.ResourceQuotas(namespace).Get(name)
+			if errors.IsNotFound(err) {
+				r.SetId("")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			readLabels(r, &item.ObjectMeta)
+			readAnnotations(r, &item.ObjectMeta)
+
+			r.Set("name", item.ObjectMeta.Name)
+			r.Set("hard", readResourceList(item.Spec.Hard, r.Get("hard")))
+			r.Set("used", readResourceList(item.Status.Used, r.Get("used")))
+			return nil
+		},
+		Update: func(r *schema.ResourceData, v interface{}) error {
+
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			item := &api.ResourceQuota{}
+			err = patchObject(client.RESTClient, "resourcequotas", r, v, func(r *schema.ResourceData) (runtime.Object, error) {
+				item := &api.ResourceQuota{}
+				item.Namespace = namespace
+				item.Name = name
+
+				err := writeResourceQuota(r, item)
+				return item, err
+			}, item)
+			if err != nil {
+				return err
+			}
+
+			r.Set("used", readResourceList(item.Status.Used, r.Get("used")))
+			return nil
+		},
+		Delete: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			return client.ResourceQuotas(namespace).Delete(name)
+		},
+		Exists: func(r *schema.ResourceData, v interface{}) (bool, error) {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return false, err
+			}
+
+			_, err = client.ResourceQuotas(namespace).Get(name)
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			return true, nil
+		},
+	}
+}
+
+func writeResourceQuota(r *schema.ResourceData, item *api.ResourceQuota) error {
+	writeLabels(r, &item.ObjectMeta)
+	writeAnnotations(r, &item.ObjectMeta)
+
+	hard, err := writeResourceList(r.Get("hard"))
+	if err != nil {
+		return err
+	}
+	item.Spec.Hard = hard
+
+	return nil
+}
+
+var resourceQuotaFieldMappings = []fieldMapping{
+	{"metadata", ""},
+	{"spec", ""},
+}
+
+func validateResourceQuotaConfig(r *schema.ResourceData) field.ErrorList {
+	item := &api.ResourceQuota{}
+	item.Namespace = r.Get("namespace").(string)
+	item.Name = r.Get("name").(string)
+
+	err := writeResourceQuota(r, item)
+	if err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "hard"), "", err.Error())}
+	}
+
+	return validation.ValidateResourceQuota(item)
+}
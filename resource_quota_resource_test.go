@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/resource"
+)
+
+func TestResourceQuotaReadQuantities(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+
+	item := &api.ResourceQuota{}
+	item.Namespace = "default"
+	item.Name = "quota"
+	item.Spec.Hard = api.ResourceList{
+		api.ResourceCPU:  resource.MustParse("0.5"),
+		api.ResourcePods: resource.MustParse("10"),
+	}
+	s.put("/api/v1/namespaces/default/resourcequotas/quota", item)
+
+	res := resourceQuotaResource()
+	r := resourceData(t, res, "default/quota", map[string]string{
+		"namespace": "default",
+		"name":      "quota",
+		"hard.#":    "2",
+		"hard.cpu":  "0.5",
+		"hard.pods": "10",
+	})
+	err := res.Read(r, s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	hard := r.Get("hard").(map[string]interface{})
+	if hard["cpu"] != "0.5" || hard["pods"] != "10" {
+		t.Fatalf("bad: %#v", hard)
+	}
+}
@@ -1,20 +1,84 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/schema"
 
 	"k8s.io/kubernetes/pkg/api"
 	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/validation"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/validation/field"
 )
 
-var secretsSetFunc = schema.HashResource(&schema.Resource{
-	Schema: map[string]*schema.Schema{
-		"name": {
-			Type:     schema.TypeString,
-			Required: true,
-		},
-	},
-})
+// Secret values never end up in the state or in plan output; only their
+// SHA-256 digests do. The set functions hash the name together with that
+// digest so that changing a value shows up as a diff. Values read from a
+// file are kept as the file name while the secret still holds the contents
+// of the file, a refresh drops the name once they differ.
+
+const secretDigestPrefix = "sha256:"
+
+func secretDigest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return secretDigestPrefix + hex.EncodeToString(sum[:])
+}
+
+func isSecretDigest(s string) bool {
+	return strings.HasPrefix(s, secretDigestPrefix) && len(s) == len(secretDigestPrefix)+2*sha256.Size
+}
+
+func secretValueStateFunc(v interface{}) string {
+	s, _ := v.(string)
+	if s == "" || isSecretDigest(s) {
+		return s
+	}
+	return secretDigest([]byte(s))
+}
+
+func secretBase64StateFunc(v interface{}) string {
+	s, _ := v.(string)
+	if s == "" || isSecretDigest(s) {
+		return s
+	}
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return secretDigest([]byte(s))
+	}
+	return secretDigest(b)
+}
+
+func secretsSetFunc(v interface{}) int {
+	m := v.(map[string]interface{})
+	name, _ := m["name"].(string)
+	return hashcode.String(name + "-" + secretValueStateFunc(m["value"]))
+}
+
+func validateSecretFile(v interface{}, _ string) ([]string, []error) {
+	_, err := ioutil.ReadFile(v.(string))
+	if err != nil {
+		return nil, []error{err}
+	}
+	return nil, nil
+}
+
+func secretsBase64SetFunc(v interface{}) int {
+	m := v.(map[string]interface{})
+	name, _ := m["name"].(string)
+	return hashcode.String(name + "-" + secretBase64StateFunc(m["value"]))
+}
 
 func secretsResource() *schema.Resource {
 
@@ -34,8 +98,8 @@ func secretsResource() *schema.Resource {
 			"type": {
 				Type:     schema.TypeString,
 				Optional: true,
+				Computed: true,
 				ForceNew: true,
-				Default:  "Opaque",
 			},
 			"data": {
 				Type:     schema.TypeSet,
@@ -48,9 +112,84 @@ func secretsResource() *schema.Resource {
 							Required: true,
 						},
 						"value": {
+							Type:      schema.TypeString,
+							Optional:  true,
+							StateFunc: secretValueStateFunc,
+						},
+						"file": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateSecretFile,
+						},
+					},
+				},
+			},
+			"data_base64": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Set:      secretsBase64SetFunc,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"value": {
+							Type:      schema.TypeString,
+							Required:  true,
+							StateFunc: secretBase64StateFunc,
+							ValidateFunc: func(v interface{}, _ string) ([]string, []error) {
+								_, err := base64.StdEncoding.DecodeString(v.(string))
+								if err != nil {
+									return nil, []error{err}
+								}
+								return nil, nil
+							},
+						},
+					},
+				},
+			},
+			"docker_registry": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				ConflictsWith: []string{"tls"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"server": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"username": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"password": {
+							Type:      schema.TypeString,
+							Required:  true,
+							StateFunc: secretValueStateFunc,
+						},
+						"email": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"tls": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				ConflictsWith: []string{"docker_registry"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"certificate": {
 							Type:     schema.TypeString,
 							Required: true,
 						},
+						"private_key": {
+							Type:      schema.TypeString,
+							Required:  true,
+							StateFunc: secretValueStateFunc,
+						},
 					},
 				},
 			},
@@ -78,25 +217,50 @@ func secretsResource() *schema.Resource {
 
 			item := &api.Secret{}
 			item.Name = name
-			item.Type = api.SecretType(r.Get("type").(string))
+			item.Type = api.SecretTypeOpaque
+			switch x, ok := r.GetOk("type"); {
+			case ok:
+				item.Type = api.SecretType(x.(string))
+			case len(r.Get("docker_registry").([]interface{})) > 0:
+				item.Type = api.SecretTypeDockercfg
+			case len(r.Get("tls").([]interface{})) > 0:
+				item.Type = api.SecretTypeTLS
+			}
 
 			writeLabels(r, &item.ObjectMeta)
 			writeAnnotations(r, &item.ObjectMeta)
-			writeSecretData(r, item)
+			err := writeSecretData(r, item)
+			if err != nil {
+				return err
+			}
+			readSecretData(r, item)
+
+			err = writeLastApplied(client.RESTClient, item)
+			if err != nil {
+				return err
+			}
 
-			item, err := client.Secrets(namespace).Create(item)
+			item, err = client.Secrets(namespace).Create(item)
 			if err != nil {
 				return err
 			}
 
 			r.SetId(join(namespace, name))
+			r.Set("type", string(item.Type))
 			return nil
 		},
 		Read: func(r *schema.ResourceData, v interface{}) error {
 			client := extractClient(v)
-			namespace, name := split(r.Id())
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
 
 			item, err := client.Secrets(namespace).Get(name)
+			if errors.IsNotFound(err) {
+				r.SetId("")
+				return nil
+			}
 			if err != nil {
 				return err
 			}
@@ -112,36 +276,73 @@ func secretsResource() *schema.Resource {
 		Update: func(r *schema.ResourceData, v interface{}) error {
 
 			client := extractClient(v)
-			namespace, name := split(r.Id())
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
 
-			item, err := client.Secrets(namespace).Get(name)
+			live, err := client.Secrets(namespace).Get(name)
 			if err != nil {
 				return err
 			}
 
-			item.Type = api.SecretType(r.Get("type").(string))
-			writeLabels(r, &item.ObjectMeta)
-			writeAnnotations(r, &item.ObjectMeta)
-			writeSecretData(r, item)
+			// the state only holds digests, so both objects start from the
+			// data of the secret itself; only the configuration rewrites it,
+			// writeSecretData takes the values that did not change from it
+			dataChanged := r.HasChange("data") || r.HasChange("data_base64") ||
+				r.HasChange("docker_registry") || r.HasChange("tls")
+
+			item := &api.Secret{}
+			err = patchObject(client.RESTClient, "secrets", r, v, func(d *schema.ResourceData) (runtime.Object, error) {
+				item := &api.Secret{}
+				item.Namespace = namespace
+				item.Name = name
+				item.Type = api.SecretType(d.Get("type").(string))
+				writeLabels(d, &item.ObjectMeta)
+				writeAnnotations(d, &item.ObjectMeta)
 
-			_, err = client.Secrets(namespace).Update(item)
+				item.Data = make(map[string][]byte, len(live.Data))
+				for k, x := range live.Data {
+					item.Data[k] = x
+				}
+				if d == r && dataChanged {
+					err := writeSecretData(d, item)
+					if err != nil {
+						return nil, err
+					}
+				}
+				return item, nil
+			}, item)
 			if err != nil {
 				return err
 			}
 
+			if dataChanged {
+				readSecretData(r, item)
+			}
 			return nil
 		},
 		Delete: func(r *schema.ResourceData, v interface{}) error {
 			client := extractClient(v)
-			namespace, name := split(r.Id())
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
 
-			return client.Secrets(namespace).Delete(name)
+			err = client.Secrets(namespace).Delete(name)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			return nil
 		},
 		Exists: func(r *schema.ResourceData, v interface{}) (bool, error) {
 			client := extractClient(v)
-			namespace, name := split(r.Id())
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return false, err
+			}
 
-			_, err := client.Secrets(namespace).Get(name)
+			_, err = client.Secrets(namespace).Get(name)
 			if errors.IsNotFound(err) {
 				return false, nil
 			}
@@ -153,27 +354,335 @@ func secretsResource() *schema.Resource {
 	}
 }
 
+// typedSecretKeys returns the data keys that are rendered from the
+// docker_registry and tls blocks. They are kept out of data and data_base64.
+func typedSecretKeys(r *schema.ResourceData) map[string]bool {
+	keys := map[string]bool{}
+	if len(r.Get("docker_registry").([]interface{})) > 0 {
+		keys[api.DockerConfigKey] = true
+	}
+	if len(r.Get("tls").([]interface{})) > 0 {
+		keys[api.TLSCertKey] = true
+		keys[api.TLSPrivateKeyKey] = true
+	}
+	return keys
+}
+
 func readSecretData(r *schema.ResourceData, s *api.Secret) {
-	var m = schema.NewSet(secretsSetFunc, nil)
-	if len(s.Data) > 0 {
-		for k, v := range s.Data {
-			m.Add(map[string]interface{}{
+	typed := typedSecretKeys(r)
+
+	files := map[string]string{}
+	if set, _ := r.Get("data").(*schema.Set); set != nil {
+		for _, p := range set.List() {
+			m := p.(map[string]interface{})
+			if file, _ := m["file"].(string); file != "" {
+				files[m["name"].(string)] = file
+			}
+		}
+	}
+
+	binary := map[string]bool{}
+	if set, _ := r.Get("data_base64").(*schema.Set); set != nil {
+		for _, p := range set.List() {
+			m := p.(map[string]interface{})
+			binary[m["name"].(string)] = true
+		}
+	}
+
+	var (
+		data       = schema.NewSet(secretsSetFunc, nil)
+		dataBase64 = schema.NewSet(secretsBase64SetFunc, nil)
+	)
+	for k, v := range s.Data {
+		if typed[k] {
+			continue
+		}
+		if file := files[k]; file != "" {
+			if b, err := ioutil.ReadFile(file); err == nil && bytes.Equal(b, v) {
+				data.Add(map[string]interface{}{
+					"name": k,
+					"file": file,
+				})
+				continue
+			}
+		}
+		if binary[k] || !utf8.Valid(v) {
+			dataBase64.Add(map[string]interface{}{
 				"name":  k,
-				"value": string(v),
+				"value": secretDigest(v),
 			})
+			continue
 		}
+		data.Add(map[string]interface{}{
+			"name":  k,
+			"value": secretDigest(v),
+		})
+	}
+	r.Set("data", data)
+	r.Set("data_base64", dataBase64)
+
+	if typed[api.DockerConfigKey] {
+		readDockerRegistry(r, s.Data[api.DockerConfigKey])
+	}
+	if typed[api.TLSCertKey] {
+		r.Set("tls", []interface{}{map[string]interface{}{
+			"certificate": string(s.Data[api.TLSCertKey]),
+			"private_key": secretDigest(s.Data[api.TLSPrivateKeyKey]),
+		}})
 	}
-	r.Set("data", m)
 }
 
-func writeSecretData(r *schema.ResourceData, s *api.Secret) {
+// writeSecretData replaces the data of s with the configured values. Values
+// that are only known by their digest are taken from the current data of s
+// as long as the digest still matches.
+func writeSecretData(r *schema.ResourceData, s *api.Secret) error {
+	if errs := validateSecretDataValues(r); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+
+	old := s.Data
+	value := func(attr, k, x string, b []byte) ([]byte, error) {
+		if !isSecretDigest(x) {
+			return b, nil
+		}
+		if b, ok := old[k]; ok && secretDigest(b) == x {
+			return b, nil
+		}
+		return nil, fmt.Errorf("%s %q: value is not known, only its digest", attr, k)
+	}
+
 	s.Data = map[string][]byte{}
 	if set, _ := r.Get("data").(*schema.Set); set != nil {
+		for _, p := range set.List() {
+			m := p.(map[string]interface{})
+			k := m["name"].(string)
+			x, _ := m["value"].(string)
+			file, _ := m["file"].(string)
+
+			if file != "" {
+				b, err := ioutil.ReadFile(file)
+				if err != nil {
+					return fmt.Errorf("data %q: %s", k, err)
+				}
+				s.Data[k] = b
+				continue
+			}
+			b, err := value("data", k, x, []byte(x))
+			if err != nil {
+				return err
+			}
+			s.Data[k] = b
+		}
+	}
+	if set, _ := r.Get("data_base64").(*schema.Set); set != nil {
 		for _, p := range set.List() {
 			m := p.(map[string]interface{})
 			k := m["name"].(string)
 			x := m["value"].(string)
-			s.Data[k] = []byte(x)
+
+			var b []byte
+			if !isSecretDigest(x) {
+				var err error
+				b, err = base64.StdEncoding.DecodeString(x)
+				if err != nil {
+					return fmt.Errorf("data_base64 %q: %s", k, err)
+				}
+			}
+			b, err := value("data_base64", k, x, b)
+			if err != nil {
+				return err
+			}
+			s.Data[k] = b
+		}
+	}
+
+	if l := r.Get("docker_registry").([]interface{}); len(l) > 0 {
+		b, err := writeDockerRegistry(l, old[api.DockerConfigKey])
+		if err != nil {
+			return err
+		}
+		s.Data[api.DockerConfigKey] = b
+	}
+
+	if l := r.Get("tls").([]interface{}); len(l) > 0 {
+		m := l[0].(map[string]interface{})
+		key, err := value("tls", api.TLSPrivateKeyKey, m["private_key"].(string), []byte(m["private_key"].(string)))
+		if err != nil {
+			return err
+		}
+		s.Data[api.TLSCertKey] = []byte(m["certificate"].(string))
+		s.Data[api.TLSPrivateKeyKey] = key
+	}
+
+	return validateSecretData(s)
+}
+
+// validateSecretData checks that the keys required by the type of s are
+// present and well formed.
+func validateSecretData(s *api.Secret) error {
+	var required []string
+	switch s.Type {
+	case api.SecretTypeDockercfg:
+		required = []string{api.DockerConfigKey}
+	case api.SecretTypeDockerConfigJson:
+		required = []string{api.DockerConfigJsonKey}
+	case api.SecretTypeSSHAuth:
+		required = []string{api.SSHAuthPrivateKey}
+	case api.SecretTypeTLS:
+		required = []string{api.TLSCertKey, api.TLSPrivateKeyKey}
+	case api.SecretTypeBasicAuth:
+		_, hasUsername := s.Data[api.BasicAuthUsernameKey]
+		_, hasPassword := s.Data[api.BasicAuthPasswordKey]
+		if !hasUsername && !hasPassword {
+			return fmt.Errorf("%s secrets need a %q or %q key", s.Type, api.BasicAuthUsernameKey, api.BasicAuthPasswordKey)
 		}
 	}
+	for _, k := range required {
+		if _, ok := s.Data[k]; !ok {
+			return fmt.Errorf("%s secrets need a %q key", s.Type, k)
+		}
+	}
+
+	switch s.Type {
+	case api.SecretTypeDockercfg:
+		var cfg map[string]dockercfgEntry
+		if err := json.Unmarshal(s.Data[api.DockerConfigKey], &cfg); err != nil {
+			return fmt.Errorf("%s for %q", err, api.DockerConfigKey)
+		}
+	case api.SecretTypeDockerConfigJson:
+		var cfg map[string]interface{}
+		if err := json.Unmarshal(s.Data[api.DockerConfigJsonKey], &cfg); err != nil {
+			return fmt.Errorf("%s for %q", err, api.DockerConfigJsonKey)
+		}
+	case api.SecretTypeTLS:
+		if _, err := tls.X509KeyPair(s.Data[api.TLSCertKey], s.Data[api.TLSPrivateKeyKey]); err != nil {
+			return fmt.Errorf("%s for %q and %q", err, api.TLSCertKey, api.TLSPrivateKeyKey)
+		}
+	}
+	return nil
+}
+
+// validateSecretDataValues checks that every element of data sets exactly one
+// of value and file.
+func validateSecretDataValues(r *schema.ResourceData) field.ErrorList {
+	var errs field.ErrorList
+	if set, _ := r.Get("data").(*schema.Set); set != nil {
+		for _, p := range set.List() {
+			m := p.(map[string]interface{})
+			k := m["name"].(string)
+			x, _ := m["value"].(string)
+			file, _ := m["file"].(string)
+
+			switch {
+			case x != "" && file != "":
+				errs = append(errs, field.Invalid(field.NewPath("data").Key(k), file, "value and file are mutually exclusive"))
+			case x == "" && file == "":
+				errs = append(errs, field.Required(field.NewPath("data").Key(k), "value or file is required"))
+			}
+		}
+	}
+	return errs
+}
+
+// dockercfgEntry is a single registry in a .dockercfg file.
+type dockercfgEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Email    string `json:"email"`
+	Auth     string `json:"auth"`
+}
+
+func readDockerRegistry(r *schema.ResourceData, data []byte) {
+	var cfg map[string]dockercfgEntry
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return
+	}
+
+	// keep the configured order and append any other registries
+	var servers []string
+	seen := map[string]bool{}
+	for _, p := range r.Get("docker_registry").([]interface{}) {
+		server := p.(map[string]interface{})["server"].(string)
+		if _, ok := cfg[server]; ok && !seen[server] {
+			servers = append(servers, server)
+			seen[server] = true
+		}
+	}
+	var extra []string
+	for server := range cfg {
+		if !seen[server] {
+			extra = append(extra, server)
+		}
+	}
+	sort.Strings(extra)
+	servers = append(servers, extra...)
+
+	var l []interface{}
+	for _, server := range servers {
+		e := cfg[server]
+		l = append(l, map[string]interface{}{
+			"server":   server,
+			"username": e.Username,
+			"password": secretDigest([]byte(e.Password)),
+			"email":    e.Email,
+		})
+	}
+	r.Set("docker_registry", l)
+}
+
+func writeDockerRegistry(l []interface{}, old []byte) ([]byte, error) {
+	var oldCfg map[string]dockercfgEntry
+	json.Unmarshal(old, &oldCfg)
+
+	cfg := map[string]dockercfgEntry{}
+	for _, p := range l {
+		m := p.(map[string]interface{})
+		server := m["server"].(string)
+		e := dockercfgEntry{
+			Username: m["username"].(string),
+			Password: m["password"].(string),
+			Email:    m["email"].(string),
+		}
+
+		if isSecretDigest(e.Password) {
+			o, ok := oldCfg[server]
+			if !ok || secretDigest([]byte(o.Password)) != e.Password {
+				return nil, fmt.Errorf("docker_registry %q: password is not known, only its digest", server)
+			}
+			e.Password = o.Password
+		}
+
+		e.Auth = base64.StdEncoding.EncodeToString([]byte(e.Username + ":" + e.Password))
+		cfg[server] = e
+	}
+
+	return json.Marshal(cfg)
+}
+
+// validateSecretConfig validates the name, labels, annotations and data keys
+// of the secret. The values are only known by their digests here, they are
+// checked by validateSecretData when the secret is written.
+func validateSecretConfig(r *schema.ResourceData) field.ErrorList {
+	item := &api.Secret{}
+	item.Namespace = r.Get("namespace").(string)
+	item.Name = r.Get("name").(string)
+	item.Type = api.SecretTypeOpaque
+	item.Data = map[string][]byte{}
+
+	writeLabels(r, &item.ObjectMeta)
+	writeAnnotations(r, &item.ObjectMeta)
+
+	for _, attr := range []string{"data", "data_base64"} {
+		if set, _ := r.Get(attr).(*schema.Set); set != nil {
+			for _, p := range set.List() {
+				item.Data[p.(map[string]interface{})["name"].(string)] = nil
+			}
+		}
+	}
+	for k := range typedSecretKeys(r) {
+		item.Data[k] = nil
+	}
+
+	errs := validateSecretDataValues(r)
+	return append(errs, validation.ValidateSecret(item)...)
 }
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+
+	"k8s.io/kubernetes/pkg/api"
+)
+
+func TestSecretDigest(t *testing.T) {
+	digest := secretDigest([]byte("hunter2"))
+	if !isSecretDigest(digest) {
+		t.Fatalf("bad: %s", digest)
+	}
+	if isSecretDigest("sha256:hunter2") {
+		t.Fatalf("bad: a short value is a digest")
+	}
+
+	// the state functions are applied to values that are digests already
+	if x := secretValueStateFunc("hunter2"); x != digest {
+		t.Fatalf("bad: %s", x)
+	}
+	if x := secretValueStateFunc(digest); x != digest {
+		t.Fatalf("bad: %s", x)
+	}
+	if x := secretBase64StateFunc(base64.StdEncoding.EncodeToString([]byte("hunter2"))); x != digest {
+		t.Fatalf("bad: %s", x)
+	}
+	if x := secretBase64StateFunc(digest); x != digest {
+		t.Fatalf("bad: %s", x)
+	}
+}
+
+// TestSecretDataRoundTrip reads the data of a secret into the state and
+// writes it back from the digests.
+func TestSecretDataRoundTrip(t *testing.T) {
+	f, err := ioutil.TempFile("", "secret")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("contents")
+	f.Close()
+
+	live := &api.Secret{
+		Type: api.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"password": []byte("hunter2"),
+			"key":      {0xff, 0x00},
+			"file":     []byte("contents"),
+		},
+	}
+
+	res := secretsResource()
+	r := resourceData(t, res, "default/web", map[string]string{"namespace": "default", "name": "web"})
+	r.Set("data", []interface{}{
+		map[string]interface{}{"name": "file", "file": f.Name()},
+	})
+	readSecretData(r, live)
+
+	if n := r.Get("data").(*schema.Set).Len(); n != 2 {
+		t.Fatalf("bad data: %d elements", n)
+	}
+	if n := r.Get("data_base64").(*schema.Set).Len(); n != 1 {
+		t.Fatalf("bad data_base64: %d elements", n)
+	}
+
+	item := &api.Secret{Type: live.Type, Data: live.Data}
+	err = writeSecretData(r, item)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(item.Data, live.Data) {
+		t.Fatalf("bad: %q", item.Data)
+	}
+
+	// the digests can't be written without the values
+	item = &api.Secret{Type: live.Type}
+	err = writeSecretData(r, item)
+	if err == nil {
+		t.Fatalf("expected an error for unknown values")
+	}
+}
+
+func TestValidateSecretDataValues(t *testing.T) {
+	f, err := ioutil.TempFile("", "secret")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(f.Name())
+	f.Close()
+
+	cases := []struct {
+		Data map[string]interface{}
+		Err  bool
+	}{
+		{Data: map[string]interface{}{"name": "a", "value": "x"}},
+		{Data: map[string]interface{}{"name": "a", "file": f.Name()}},
+		{Data: map[string]interface{}{"name": "a", "value": "x", "file": f.Name()}, Err: true},
+		{Data: map[string]interface{}{"name": "a"}, Err: true},
+	}
+
+	res := secretsResource()
+	for i, tc := range cases {
+		r := resourceData(t, res, "default/web", map[string]string{"namespace": "default", "name": "web"})
+		r.Set("data", []interface{}{tc.Data})
+
+		errs := validateSecretDataValues(r)
+		if (len(errs) > 0) != tc.Err {
+			t.Fatalf("%d: errs: %v", i, errs)
+		}
+		if (len(validateSecretConfig(r)) > 0) != tc.Err {
+			t.Fatalf("%d: bad validation", i)
+		}
+		if (writeSecretData(r, &api.Secret{Type: api.SecretTypeOpaque}) != nil) != tc.Err {
+			t.Fatalf("%d: bad write", i)
+		}
+	}
+}
+
+func TestDockerRegistryRoundTrip(t *testing.T) {
+	res := secretsResource()
+	r := resourceData(t, res, "default/registry", map[string]string{"namespace": "default", "name": "registry"})
+	r.Set("docker_registry", []interface{}{
+		map[string]interface{}{"server": "b.example.com", "username": "bob", "password": "hunter2", "email": ""},
+		map[string]interface{}{"server": "a.example.com", "username": "alice", "password": "secret", "email": "alice@example.com"},
+	})
+
+	item := &api.Secret{Type: api.SecretTypeDockercfg}
+	err := writeSecretData(r, item)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var cfg map[string]dockercfgEntry
+	err = json.Unmarshal(item.Data[api.DockerConfigKey], &cfg)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if e := cfg["b.example.com"]; e.Password != "hunter2" || e.Auth != base64.StdEncoding.EncodeToString([]byte("bob:hunter2")) {
+		t.Fatalf("bad: %#v", e)
+	}
+
+	// the state keeps the configured order and only the digests of the
+	// passwords, which are enough to write the secret again
+	readSecretData(r, item)
+	l := r.Get("docker_registry").([]interface{})
+	if len(l) != 2 || l[0].(map[string]interface{})["server"] != "b.example.com" {
+		t.Fatalf("bad: %#v", l)
+	}
+	if x := l[0].(map[string]interface{})["password"]; x != secretDigest([]byte("hunter2")) {
+		t.Fatalf("bad password: %s", x)
+	}
+	if n := r.Get("data").(*schema.Set).Len(); n != 0 {
+		t.Fatalf("bad data: %d elements", n)
+	}
+
+	written := &api.Secret{Type: item.Type, Data: item.Data}
+	err = writeSecretData(r, written)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(written.Data, item.Data) {
+		t.Fatalf("bad: %s", written.Data[api.DockerConfigKey])
+	}
+
+	// a digest that doesn't match the current password can't be written
+	written = &api.Secret{Type: item.Type, Data: map[string][]byte{api.DockerConfigKey: []byte("{}")}}
+	err = writeSecretData(r, written)
+	if err == nil {
+		t.Fatalf("expected an error for an unknown password")
+	}
+}
+
+func TestTLSSecret(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	cert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+
+	cases := []struct {
+		Certificate string
+		PrivateKey  string
+		Err         bool
+	}{
+		{Certificate: cert, PrivateKey: privateKey},
+		{Certificate: cert, PrivateKey: "not a key", Err: true},
+		{Certificate: "not a certificate", PrivateKey: privateKey, Err: true},
+	}
+
+	res := secretsResource()
+	for i, tc := range cases {
+		r := resourceData(t, res, "default/tls", map[string]string{"namespace": "default", "name": "tls"})
+		r.Set("tls", []interface{}{
+			map[string]interface{}{"certificate": tc.Certificate, "private_key": tc.PrivateKey},
+		})
+
+		item := &api.Secret{Type: api.SecretTypeTLS}
+		err := writeSecretData(r, item)
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: err: %v", i, err)
+		}
+		if err != nil {
+			continue
+		}
+
+		readSecretData(r, item)
+		m := r.Get("tls").([]interface{})[0].(map[string]interface{})
+		if m["certificate"] != cert || m["private_key"] != secretDigest([]byte(privateKey)) {
+			t.Fatalf("%d: bad: %#v", i, m)
+		}
+		if n := r.Get("data").(*schema.Set).Len(); n != 0 {
+			t.Fatalf("%d: bad data: %d elements", i, n)
+		}
+	}
+}
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/runtime"
+)
+
+// fakeServer is an in-memory API server for the tests. It stores objects as
+// JSON under their REST path and understands enough of the API for the
+// resources: get, list with a label selector, create, update, merge patches
+// and delete. The react hook plays the part of the controllers.
+type fakeServer struct {
+	*httptest.Server
+	t *testing.T
+
+	mu      sync.Mutex
+	objects map[string]map[string]interface{}
+	version int
+
+	// react is called with every object that is written, and with nil for
+	// every object that is deleted
+	react func(s *fakeServer, p string, obj map[string]interface{})
+
+	// conflicts is the number of updates that lose a race against a
+	// concurrent writer, which changes the object first
+	conflicts int
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	s := &fakeServer{
+		t:       t,
+		objects: make(map[string]map[string]interface{}),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// meta returns the provider meta with a client for s.
+func (s *fakeServer) meta() *providerMeta {
+	c, err := client.New(&client.Config{Host: s.URL})
+	if err != nil {
+		s.t.Fatalf("err: %s", err)
+	}
+	return &providerMeta{client: c, owner: defaultOwner}
+}
+
+// put stores obj under the REST path p.
+func (s *fakeServer) put(p string, obj runtime.Object) {
+	m, t, err := versionedMap(obj, apiVersionOf(p))
+	if err != nil {
+		s.t.Fatalf("err: %s", err)
+	}
+	m["kind"] = t.Elem().Name()
+	m["apiVersion"] = apiVersionOf(p)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store(p, m)
+}
+
+// get decodes the object stored under p into obj, it returns false if there
+// is none.
+func (s *fakeServer) get(p string, obj interface{}) bool {
+	s.mu.Lock()
+	m, ok := s.objects[p]
+	s.mu.Unlock()
+	if !ok {
+		return false
+	}
+	data, _ := json.Marshal(m)
+	err := json.Unmarshal(data, obj)
+	if err != nil {
+		s.t.Fatalf("err: %s", err)
+	}
+	return true
+}
+
+// list returns the paths of the objects in the collection p.
+func (s *fakeServer) list(p string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var l []string
+	for k := range s.objects {
+		if path.Dir(k) == p {
+			l = append(l, k)
+		}
+	}
+	return l
+}
+
+func (s *fakeServer) delete(p string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, p)
+}
+
+func (s *fakeServer) store(p string, m map[string]interface{}) {
+	meta, _ := m["metadata"].(map[string]interface{})
+	if meta == nil {
+		meta = make(map[string]interface{})
+		m["metadata"] = meta
+	}
+
+	s.version++
+	meta["resourceVersion"] = strconv.Itoa(s.version)
+	meta["name"] = path.Base(p)
+	if parts := strings.Split(p, "/"); len(parts) > 4 && parts[len(parts)-4] == "namespaces" {
+		meta["namespace"] = parts[len(parts)-3]
+	}
+	if _, ok := meta["creationTimestamp"]; !ok {
+		meta["creationTimestamp"] = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	generation := 1.0
+	if old, ok := s.objects[p]; ok {
+		generation, _ = old["metadata"].(map[string]interface{})["generation"].(float64)
+		if !reflect.DeepEqual(old["spec"], m["spec"]) {
+			generation++
+		}
+	}
+	meta["generation"] = generation
+
+	s.objects[p] = m
+	if s.react != nil {
+		s.react(s, p, m)
+	}
+}
+
+func (s *fakeServer) serve(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := req.URL.Path
+	body, _ := ioutil.ReadAll(req.Body)
+
+	switch req.Method {
+	case "GET":
+		if m, ok := s.objects[p]; ok {
+			s.reply(w, http.StatusOK, m)
+			return
+		}
+		if l, ok := fakeResources[strings.TrimPrefix(strings.TrimPrefix(p, "/api/"), "/apis/")]; ok {
+			s.reply(w, http.StatusOK, map[string]interface{}{
+				"kind":         "APIResourceList",
+				"groupVersion": apiVersionOf(p),
+				"resources":    l,
+			})
+			return
+		}
+		if !s.isCollection(p) {
+			s.fail(w, http.StatusNotFound, "NotFound", p)
+			return
+		}
+		selector, err := labels.Parse(req.URL.Query().Get("labelSelector"))
+		if err != nil {
+			s.fail(w, http.StatusBadRequest, "BadRequest", p)
+			return
+		}
+		// an empty list decodes as any list
+		kind := ""
+		items := []interface{}{}
+		for k, m := range s.objects {
+			if path.Dir(k) != p {
+				continue
+			}
+			l := labels.Set{}
+			meta, _ := m["metadata"].(map[string]interface{})
+			x, _ := meta["labels"].(map[string]interface{})
+			for k, v := range x {
+				l[k], _ = v.(string)
+			}
+			if selector.Matches(l) {
+				kind, _ = m["kind"].(string)
+				items = append(items, m)
+			}
+		}
+		s.reply(w, http.StatusOK, map[string]interface{}{
+			"kind":       kind + "List",
+			"apiVersion": apiVersionOf(p),
+			"metadata":   map[string]interface{}{},
+			"items":      items,
+		})
+
+	case "POST":
+		var m map[string]interface{}
+		if json.Unmarshal(body, &m) != nil {
+			s.fail(w, http.StatusBadRequest, "BadRequest", p)
+			return
+		}
+		meta, _ := m["metadata"].(map[string]interface{})
+		name, _ := meta["name"].(string)
+		p = path.Join(p, name)
+		if _, ok := s.objects[p]; ok {
+			s.fail(w, http.StatusConflict, "AlreadyExists", p)
+			return
+		}
+		s.store(p, m)
+		s.reply(w, http.StatusCreated, m)
+
+	case "PUT":
+		var m map[string]interface{}
+		if json.Unmarshal(body, &m) != nil {
+			s.fail(w, http.StatusBadRequest, "BadRequest", p)
+			return
+		}
+		old, ok := s.objects[p]
+		if !ok {
+			s.fail(w, http.StatusNotFound, "NotFound", p)
+			return
+		}
+		if s.conflicts > 0 {
+			s.conflicts--
+			s.store(p, old)
+		}
+		meta, _ := m["metadata"].(map[string]interface{})
+		if v, _ := meta["resourceVersion"].(string); v != "" && v != old["metadata"].(map[string]interface{})["resourceVersion"] {
+			s.fail(w, http.StatusConflict, "Conflict", p)
+			return
+		}
+		s.store(p, m)
+		s.reply(w, http.StatusOK, m)
+
+	case "PATCH":
+		var patch map[string]interface{}
+		if json.Unmarshal(body, &patch) != nil {
+			s.fail(w, http.StatusBadRequest, "BadRequest", p)
+			return
+		}
+		old, ok := s.objects[p]
+		if !ok {
+			s.fail(w, http.StatusNotFound, "NotFound", p)
+			return
+		}
+		m := applyMergePatch(copyJSON(old), patch).(map[string]interface{})
+		s.store(p, m)
+		s.reply(w, http.StatusOK, m)
+
+	case "DELETE":
+		if _, ok := s.objects[p]; !ok {
+			s.fail(w, http.StatusNotFound, "NotFound", p)
+			return
+		}
+		delete(s.objects, p)
+		if s.react != nil {
+			s.react(s, p, nil)
+		}
+		s.reply(w, http.StatusOK, map[string]interface{}{
+			"kind":       "Status",
+			"apiVersion": "v1",
+			"status":     "Success",
+		})
+	}
+}
+
+// isCollection reports whether p names a resource, like
+// /api/v1/namespaces/default/pods, rather than an object.
+func (s *fakeServer) isCollection(p string) bool {
+	parts := strings.Split(strings.Trim(p, "/"), "/")
+	if parts[0] == "apis" {
+		parts = parts[1:]
+	}
+	parts = parts[2:]
+	if len(parts) >= 2 && parts[0] == "namespaces" {
+		parts = parts[2:]
+	}
+	return len(parts) == 1
+}
+
+func (s *fakeServer) reply(w http.ResponseWriter, code int, obj interface{}) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		s.t.Errorf("err: %s", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+func (s *fakeServer) fail(w http.ResponseWriter, code int, reason, p string) {
+	s.reply(w, code, map[string]interface{}{
+		"kind":       "Status",
+		"apiVersion": "v1",
+		"status":     "Failure",
+		"reason":     reason,
+		"code":       code,
+		"message":    fmt.Sprintf("%s: %s", reason, p),
+		"details":    map[string]interface{}{"name": path.Base(p)},
+	})
+}
+
+// fakeResources are the resources of the fake server's discovery, by group
+// version.
+var fakeResources = map[string][]map[string]interface{}{
+	"v1": {
+		{"name": "configmaps", "namespaced": true, "kind": "ConfigMap"},
+		{"name": "namespaces", "namespaced": false, "kind": "Namespace"},
+		{"name": "pods", "namespaced": true, "kind": "Pod"},
+		{"name": "pods/log", "namespaced": true, "kind": "Pod"},
+	},
+	"example.com/v1": {
+		{"name": "widgets", "namespaced": true, "kind": "Widget"},
+	},
+}
+
+func apiVersionOf(p string) string {
+	parts := strings.Split(strings.Trim(p, "/"), "/")
+	if parts[0] == "apis" {
+		return parts[1] + "/" + parts[2]
+	}
+	return parts[1]
+}
+
+// applyMergePatch applies a JSON merge patch, the directives of strategic
+// merge patches replace lists.
+func applyMergePatch(dst, patch interface{}) interface{} {
+	switch p := patch.(type) {
+	case map[string]interface{}:
+		m, ok := dst.(map[string]interface{})
+		if !ok {
+			m = make(map[string]interface{})
+		}
+		for k, x := range p {
+			if x == nil {
+				delete(m, k)
+				continue
+			}
+			m[k] = applyMergePatch(m[k], x)
+		}
+		return m
+	case []interface{}:
+		var l []interface{}
+		for _, x := range p {
+			if d, ok := x.(map[string]interface{}); ok && d["$patch"] != nil {
+				continue
+			}
+			l = append(l, x)
+		}
+		return l
+	}
+	return patch
+}
+
+func copyJSON(m map[string]interface{}) map[string]interface{} {
+	data, _ := json.Marshal(m)
+	var c map[string]interface{}
+	json.Unmarshal(data, &c)
+	return c
+}
+
+// providerResource wraps res as the provider does.
+func providerResource(res *schema.Resource) *schema.Resource {
+	trackPreviousState(res)
+	enforceOwnership(res)
+	ignoreMetadata(res)
+	return res
+}
+
+// apply diffs the configuration raw against state and applies the diff
+// with the provider meta v.
+func apply(t *testing.T, res *schema.Resource, state *terraform.InstanceState, raw map[string]interface{}, v interface{}) (*terraform.InstanceState, error) {
+	rc, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	d, err := res.Diff(state, terraform.NewResourceConfig(rc))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if state == nil {
+		state = &terraform.InstanceState{}
+	}
+	return res.Apply(state, d, v)
+}
+
+// resourceData returns the data of res for a state with the given id and
+// attributes.
+func resourceData(t *testing.T, res *schema.Resource, id string, attributes map[string]string) *schema.ResourceData {
+	v, err := withPrevious(res.Schema, &terraform.InstanceState{ID: id, Attributes: attributes}, &providerMeta{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return extractPrevious(v)
+}
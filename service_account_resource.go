@@ -0,0 +1,280 @@
+package main
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/validation"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+	"k8s.io/kubernetes/pkg/util/wait"
+)
+
+func serviceAccountResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "default",
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"annotations": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			"secret": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+			"image_pull_secret": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+
+			"default_secret_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		Create: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace := r.Get("namespace").(string)
+			name := r.Get("name").(string)
+
+			item := &api.ServiceAccount{}
+			item.Name = name
+			writeServiceAccount(r, item, "")
+
+			err := writeLastApplied(client.RESTClient, item)
+			if err != nil {
+				return err
+			}
+
+			item, err = client.ServiceAccounts(namespace).Create(item)
+			if err != nil {
+				return err
+			}
+
+			r.SetId(join(namespace, name))
+
+			// the token controller adds the token secret shortly after
+			var token string
+			err = wait.Poll(1*time.Second, 2*time.Minute, func() (bool, error) {
+				item, err := client.ServiceAccounts(namespace).Get(name)
+				if err != nil {
+					return false, err
+				}
+				token, err = findServiceAccountToken(client, item)
+				return token != "", err
+			})
+			if err != nil {
+				return err
+			}
+
+			r.Set("default_secret_name", token)
+			return nil
+		},
+		Read: resourceServiceAccountRead,
+		Update: func(r *schema.ResourceData, v interface{}) error {
+
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			item, err := client.ServiceAccounts(namespace).Get(name)
+			if err != nil {
+				return err
+			}
+
+			token, err := findServiceAccountToken(client, item)
+			if err != nil {
+				return err
+			}
+
+			err = patchObject(client.RESTClient, "serviceaccounts", r, v, func(r *schema.ResourceData) (runtime.Object, error) {
+				item := &api.ServiceAccount{}
+				item.Namespace = namespace
+				item.Name = name
+
+				writeServiceAccount(r, item, token)
+				return item, nil
+			}, &api.ServiceAccount{})
+			if err != nil {
+				return err
+			}
+
+			return resourceServiceAccountRead(r, v)
+		},
+		Delete: func(r *schema.ResourceData, v interface{}) error {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
+
+			return client.ServiceAccounts(namespace).Delete(name)
+		},
+		Exists: func(r *schema.ResourceData, v interface{}) (bool, error) {
+			client := extractClient(v)
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return false, err
+			}
+
+			_, err = client.ServiceAccounts(namespace).Get(name)
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			return true, nil
+		},
+	}
+}
+
+func resourceServiceAccountRead(r *schema.ResourceData, v interface{}) error {
+	client := extractClient(v)
+	namespace, name, err := split(r.Id())
+	if err != nil {
+		return err
+	}
+
+	item, err := client.ServiceAccounts(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		r.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := findServiceAccountToken(client, item)
+	if err != nil {
+		return err
+	}
+
+	readLabels(r, &item.ObjectMeta)
+	readAnnotations(r, &item.ObjectMeta)
+	readServiceAccount(r, item, token)
+
+	r.Set("name", item.ObjectMeta.Name)
+	r.Set("default_secret_name", token)
+	return nil
+}
+
+// findServiceAccountToken returns the name of the token secret the token
+// controller generated for the service account, or "" if there is none yet.
+func findServiceAccountToken(c client.Interface, item *api.ServiceAccount) (string, error) {
+	for _, ref := range item.Secrets {
+		secret, err := c.Secrets(item.Namespace).Get(ref.Name)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if secret.Type == api.SecretTypeServiceAccountToken &&
+			secret.Annotations[api.ServiceAccountNameKey] == item.Name {
+			return secret.Name, nil
+		}
+	}
+	return "", nil
+}
+
+func readServiceAccount(r *schema.ResourceData, item *api.ServiceAccount, token string) {
+	var secrets []interface{}
+	for _, ref := range item.Secrets {
+		if ref.Name == token {
+			continue
+		}
+		secrets = append(secrets, map[string]interface{}{
+			"name": ref.Name,
+		})
+	}
+	r.Set("secret", secrets)
+
+	var imagePullSecrets []interface{}
+	for _, ref := range item.ImagePullSecrets {
+		imagePullSecrets = append(imagePullSecrets, map[string]interface{}{
+			"name": ref.Name,
+		})
+	}
+	r.Set("image_pull_secret", imagePullSecrets)
+}
+
+// writeServiceAccount keeps the generated token secret in the list of
+// secrets, it is not part of the configuration.
+func writeServiceAccount(r *schema.ResourceData, item *api.ServiceAccount, token string) {
+	writeLabels(r, &item.ObjectMeta)
+	writeAnnotations(r, &item.ObjectMeta)
+
+	item.Secrets = nil
+	if token != "" {
+		item.Secrets = append(item.Secrets, api.ObjectReference{Name: token})
+	}
+	if l, _ := r.Get("secret").([]interface{}); l != nil {
+		for _, v := range l {
+			name, _ := v.(map[string]interface{})["name"].(string)
+			item.Secrets = append(item.Secrets, api.ObjectReference{Name: name})
+		}
+	}
+
+	item.ImagePullSecrets = nil
+	if l, _ := r.Get("image_pull_secret").([]interface{}); l != nil {
+		for _, v := range l {
+			ref := api.LocalObjectReference{}
+			writePodImagePullSecret(v.(map[string]interface{}), &ref)
+			item.ImagePullSecrets = append(item.ImagePullSecrets, ref)
+		}
+	}
+}
+
+func validateServiceAccountConfig(r *schema.ResourceData) field.ErrorList {
+	item := &api.ServiceAccount{}
+	item.Namespace = r.Get("namespace").(string)
+	item.Name = r.Get("name").(string)
+	writeServiceAccount(r, item, "")
+
+	return validation.ValidateServiceAccount(item)
+}
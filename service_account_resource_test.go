@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+
+	"k8s.io/kubernetes/pkg/api"
+)
+
+// TestServiceAccountUpdate updates a service account whose token was
+// replaced since the last refresh.
+func TestServiceAccountUpdate(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+
+	token := &api.Secret{}
+	token.Type = api.SecretTypeServiceAccountToken
+	token.Annotations = map[string]string{api.ServiceAccountNameKey: "web"}
+	s.put("/api/v1/namespaces/default/secrets/web-token-2", token)
+
+	item := &api.ServiceAccount{}
+	item.Annotations = map[string]string{ownedAnnotation: defaultOwner}
+	item.Secrets = []api.ObjectReference{{Name: "web-token-2"}}
+	s.put("/api/v1/namespaces/default/serviceaccounts/web", item)
+
+	state := &terraform.InstanceState{
+		ID: "default/web",
+		Attributes: map[string]string{
+			"namespace":           "default",
+			"name":                "web",
+			"owner":               defaultOwner,
+			"default_secret_name": "web-token-1",
+		},
+	}
+	raw := map[string]interface{}{
+		"name":   "web",
+		"labels": map[string]interface{}{"app": "web"},
+	}
+
+	res := providerResource(serviceAccountResource())
+	state, err := apply(t, res, state, raw, s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]string{
+		"labels.app":          "web",
+		"default_secret_name": "web-token-2",
+		"secret.#":            "0",
+	}
+	for k, x := range expected {
+		if state.Attributes[k] != x {
+			t.Fatalf("bad %s: %q", k, state.Attributes[k])
+		}
+	}
+}
@@ -5,7 +5,10 @@ import (
 
 	"k8s.io/kubernetes/pkg/api"
 	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/validation"
+	"k8s.io/kubernetes/pkg/runtime"
 	"k8s.io/kubernetes/pkg/util/intstr"
+	"k8s.io/kubernetes/pkg/util/validation/field"
 )
 
 func serviceResource() *schema.Resource {
@@ -133,23 +136,14 @@ func serviceResource() *schema.Resource {
 
 			item := &api.Service{}
 			item.Name = name
-			item.Spec.Type = api.ServiceType(r.Get("type").(string))
-			item.Spec.SessionAffinity = api.ServiceAffinity(r.Get("session_affinity").(string))
+			writeService(r, item)
 
-			if v, ok := r.GetOk("cluster_ip"); ok {
-				item.Spec.ClusterIP = v.(string)
-			}
-			if v, ok := r.GetOk("load_balancer_ip"); ok {
-				item.Spec.LoadBalancerIP = v.(string)
+			err := writeLastApplied(client.RESTClient, item)
+			if err != nil {
+				return err
 			}
 
-			writeExternalIPs(r, &item.Spec)
-			writePorts(r, &item.Spec)
-			writeSelectors(r, &item.Spec)
-			writeLabels(r, &item.ObjectMeta)
-			writeAnnotations(r, &item.ObjectMeta)
-
-			item, err := client.Services(namespace).Create(item)
+			item, err = client.Services(namespace).Create(item)
 			if err != nil {
 				return err
 			}
@@ -158,15 +152,22 @@ func serviceResource() *schema.Resource {
 			r.Set("load_balancer_ip", string(item.Spec.LoadBalancerIP))
 			r.Set("cluster_ip", string(item.Spec.ClusterIP))
 			readPorts(r, &item.Spec)
-			readLoadBalancerIngressIPs(r, &item.Status)
+			readLoadBalancerIngressIPs(r, &item.Status.LoadBalancer)
 
 			return nil
 		},
 		Read: func(r *schema.ResourceData, v interface{}) error {
 			client := extractClient(v)
-			namespace, name := split(r.Id())
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
 
 			item, err := client.Services(namespace).Get(name)
+			if errors.IsNotFound(err) {
+				r.SetId("")
+				return nil
+			}
 			if err != nil {
 				return err
 			}
@@ -176,7 +177,7 @@ func serviceResource() *schema.Resource {
 			readSelectors(r, &item.Spec)
 			readLabels(r, &item.ObjectMeta)
 			readAnnotations(r, &item.ObjectMeta)
-			readLoadBalancerIngressIPs(r, &item.Status)
+			readLoadBalancerIngressIPs(r, &item.Status.LoadBalancer)
 
 			r.Set("type", string(item.Spec.Type))
 			r.Set("session_affinity", string(item.Spec.SessionAffinity))
@@ -188,30 +189,20 @@ func serviceResource() *schema.Resource {
 		Update: func(r *schema.ResourceData, v interface{}) error {
 
 			client := extractClient(v)
-			namespace, name := split(r.Id())
-
-			item, err := client.Services(namespace).Get(name)
+			namespace, name, err := split(r.Id())
 			if err != nil {
 				return err
 			}
 
-			item.Spec.Type = api.ServiceType(r.Get("type").(string))
-			item.Spec.SessionAffinity = api.ServiceAffinity(r.Get("session_affinity").(string))
-
-			if v, ok := r.GetOk("cluster_ip"); ok {
-				item.Spec.ClusterIP = v.(string)
-			}
-			if v, ok := r.GetOk("load_balancer_ip"); ok {
-				item.Spec.LoadBalancerIP = v.(string)
-			}
-
-			writeExternalIPs(r, &item.Spec)
-			writePorts(r, &item.Spec)
-			writeSelectors(r, &item.Spec)
-			writeLabels(r, &item.ObjectMeta)
-			writeAnnotations(r, &item.ObjectMeta)
-
-			item, err = client.Services(namespace).Update(item)
+			item := &api.Service{}
+			err = patchObject(client.RESTClient, "services", r, v, func(r *schema.ResourceData) (runtime.Object, error) {
+				item := &api.Service{}
+				item.Namespace = namespace
+				item.Name = name
+
+				writeService(r, item)
+				return item, nil
+			}, item)
 			if err != nil {
 				return err
 			}
@@ -219,21 +210,26 @@ func serviceResource() *schema.Resource {
 			r.Set("load_balancer_ip", string(item.Spec.LoadBalancerIP))
 			r.Set("cluster_ip", string(item.Spec.ClusterIP))
 			readPorts(r, &item.Spec)
-			readLoadBalancerIngressIPs(r, &item.Status)
-
+			readLoadBalancerIngressIPs(r, &item.Status.LoadBalancer)
 			return nil
 		},
 		Delete: func(r *schema.ResourceData, v interface{}) error {
 			client := extractClient(v)
-			namespace, name := split(r.Id())
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return err
+			}
 
 			return client.Services(namespace).Delete(name)
 		},
 		Exists: func(r *schema.ResourceData, v interface{}) (bool, error) {
 			client := extractClient(v)
-			namespace, name := split(r.Id())
+			namespace, name, err := split(r.Id())
+			if err != nil {
+				return false, err
+			}
 
-			_, err := client.Services(namespace).Get(name)
+			_, err = client.Services(namespace).Get(name)
 			if errors.IsNotFound(err) {
 				return false, nil
 			}
@@ -245,6 +241,50 @@ func serviceResource() *schema.Resource {
 	}
 }
 
+func writeService(r *schema.ResourceData, item *api.Service) {
+	item.Spec.Type = api.ServiceType(r.Get("type").(string))
+	item.Spec.SessionAffinity = api.ServiceAffinity(r.Get("session_affinity").(string))
+
+	if v, ok := r.GetOk("cluster_ip"); ok {
+		item.Spec.ClusterIP = v.(string)
+	}
+	if v, ok := r.GetOk("load_balancer_ip"); ok {
+		item.Spec.LoadBalancerIP = v.(string)
+	}
+
+	writeExternalIPs(r, &item.Spec)
+	writePorts(r, &item.Spec)
+	writeSelectors(r, &item.Spec)
+	writeLabels(r, &item.ObjectMeta)
+	writeAnnotations(r, &item.ObjectMeta)
+}
+
+var serviceFieldMappings = []fieldMapping{
+	{"metadata", ""},
+	{"spec", ""},
+}
+
+func validateServiceConfig(r *schema.ResourceData) field.ErrorList {
+	item := &api.Service{}
+	item.Namespace = r.Get("namespace").(string)
+	item.Name = r.Get("name").(string)
+	writeService(r, item)
+
+	err := applyDefaults(item, "v1")
+	if err != nil {
+		return field.ErrorList{field.InternalError(field.NewPath("spec"), err)}
+	}
+
+	// the target port defaults to the port on the server
+	for i, p := range item.Spec.Ports {
+		if p.TargetPort.IntValue() == 0 {
+			item.Spec.Ports[i].TargetPort = intstr.FromInt(p.Port)
+		}
+	}
+
+	return validation.ValidateService(item)
+}
+
 func readSelectors(r *schema.ResourceData, spec *api.ServiceSpec) {
 	m := make(map[string]interface{})
 	if len(spec.Selector) > 0 {
@@ -276,10 +316,10 @@ func readExternalIPs(r *schema.ResourceData, spec *api.ServiceSpec) {
 	r.Set("external_ips", m)
 }
 
-func readLoadBalancerIngressIPs(r *schema.ResourceData, stat *api.ServiceStatus) {
+func readLoadBalancerIngressIPs(r *schema.ResourceData, stat *api.LoadBalancerStatus) {
 	var m []interface{}
-	if len(stat.LoadBalancer.Ingress) > 0 {
-		for _, v := range stat.LoadBalancer.Ingress {
+	if len(stat.Ingress) > 0 {
+		for _, v := range stat.Ingress {
 			m = append(m, map[string]interface{}{
 				"ip":       v.IP,
 				"hostname": v.Hostname,
@@ -1,12 +1,15 @@
 package main
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 
 	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/resource"
 	"k8s.io/kubernetes/pkg/client/unversioned"
 )
 
@@ -14,6 +17,10 @@ type Setter interface {
 	Set(key string, value interface{}) error
 }
 
+type ListParent interface {
+	NewList(key string) *ListBuilder
+}
+
 type ObjectBuilder struct {
 	parent Setter
 	key    string
@@ -104,65 +111,164 @@ func (b *ListBuilder) Next() {
 	}
 }
 
+// providerMeta is handed to the resources by the provider.
+type providerMeta struct {
+	client *unversioned.Client
+	owner  string
+
+	ignoreLabels      []string
+	ignoreAnnotations []string
+
+	// previous holds the data of the previous state during an Update
+	previous *schema.ResourceData
+}
+
 func extractClient(v interface{}) *unversioned.Client {
-	return v.(*unversioned.Client)
+	return v.(*providerMeta).client
+}
+
+func extractOwner(v interface{}) string {
+	return v.(*providerMeta).owner
+}
+
+func extractPrevious(v interface{}) *schema.ResourceData {
+	return v.(*providerMeta).previous
 }
 
+// readLabels only reads the labels that are in the state, labels added by
+// controllers or kubectl are not managed by Terraform.
 func readLabels(r *schema.ResourceData, meta *api.ObjectMeta) {
-	m := make(map[string]interface{})
-	if len(meta.Labels) > 0 {
-		for k, v := range meta.Labels {
-			m[k] = v
-		}
-	}
-	r.Set("labels", m)
+	r.Set("labels", readManagedKeys(r.Get("labels"), meta.Labels))
 }
 
 func readAnnotations(r *schema.ResourceData, meta *api.ObjectMeta) {
-	m := make(map[string]interface{})
-	if len(meta.Annotations) > 0 {
-		for k, v := range meta.Annotations {
-			if k == "terraform.io/owned" {
-				continue
-			}
-			m[k] = v
-		}
-	}
-	r.Set("annotations", m)
+	r.Set("annotations", readManagedKeys(r.Get("annotations"), meta.Annotations))
+	readOwner(r, meta.Annotations)
 }
 
+// writeLabels sets the configured labels and removes the ones Terraform
+// wrote before that are no longer configured, other labels are kept.
 func writeLabels(r *schema.ResourceData, meta *api.ObjectMeta) {
-	meta.Labels = map[string]string{}
-	if labels, _ := r.Get("labels").(map[string]interface{}); labels != nil {
-		for k, v := range labels {
-			if s, ok := v.(string); ok {
-				meta.Labels[k] = s
+	meta.Labels = writeManagedKeys(r, "labels", meta.Labels)
+}
+
+func writeAnnotations(r *schema.ResourceData, meta *api.ObjectMeta) {
+	meta.Annotations = writeManagedKeys(r, "annotations", meta.Annotations)
+	writeOwner(r, meta.Annotations)
+}
+
+func readManagedKeys(managed interface{}, live map[string]string) map[string]interface{} {
+	m := make(map[string]interface{})
+	if keys, _ := managed.(map[string]interface{}); keys != nil {
+		for k := range keys {
+			if v, ok := live[k]; ok {
+				m[k] = v
 			}
 		}
 	}
+	return m
 }
 
-func writeAnnotations(r *schema.ResourceData, meta *api.ObjectMeta) {
-	meta.Annotations = map[string]string{
-		"terraform.io/owned": "true",
+func writeManagedKeys(r *schema.ResourceData, key string, live map[string]string) map[string]string {
+	m := make(map[string]string, len(live))
+	for k, v := range live {
+		m[k] = v
+	}
+
+	o, _ := r.GetChange(key)
+	if old, _ := o.(map[string]interface{}); old != nil {
+		for k := range old {
+			delete(m, k)
+		}
 	}
-	if annotations, _ := r.Get("annotations").(map[string]interface{}); annotations != nil {
-		for k, v := range annotations {
+	if config, _ := r.Get(key).(map[string]interface{}); config != nil {
+		for k, v := range config {
 			if s, ok := v.(string); ok {
-				meta.Annotations[k] = s
+				m[k] = s
 			}
 		}
 	}
+	return m
 }
 
 func join(namespace, name string) string {
 	return namespace + "/" + name
 }
 
-func split(id string) (string, string) {
+func split(id string) (string, string, error) {
 	parts := strings.Split(id, "/")
-	if len(parts) != 2 {
-		panic("invalid id: " + strconv.Quote(id))
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid id: %s, expected <namespace>/<name>", strconv.Quote(id))
+	}
+	return parts[0], parts[1], nil
+}
+
+// retryUpdate runs a get, modify and update cycle again on a fresh copy of
+// the object when the update conflicts with a concurrent change, e.g. a
+// controller writing the status.
+func retryUpdate(fn func() error) error {
+	return unversioned.RetryOnConflict(unversioned.DefaultBackoff, fn)
+}
+
+// readDuration parses the duration attribute key. States written before the
+// attribute existed don't have it, def is used for them.
+func readDuration(r *schema.ResourceData, key string, def time.Duration) (time.Duration, error) {
+	s, _ := r.Get(key).(string)
+	if s == "" {
+		return def, nil
+	}
+	return time.ParseDuration(s)
+}
+
+func validateDuration(v interface{}, _ string) ([]string, []error) {
+	_, err := time.ParseDuration(v.(string))
+	if err != nil {
+		return nil, []error{err}
+	}
+	return nil, nil
+}
+
+func validateQuantityMap(v interface{}, _ string) ([]string, []error) {
+	var errs []error
+	for k, x := range v.(map[string]interface{}) {
+		s, _ := x.(string)
+		_, err := resource.ParseQuantity(s)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s for %q", k, err, s))
+		}
+	}
+	return nil, errs
+}
+
+// readResourceList returns the quantities of l. The server stores them in
+// canonical form ("0.5Gi" becomes "512Mi"), the values of current are kept as
+// long as they are the same quantity.
+func readResourceList(l api.ResourceList, current interface{}) map[string]interface{} {
+	c, _ := current.(map[string]interface{})
+	m := make(map[string]interface{})
+	for k, v := range l {
+		if s, ok := c[string(k)].(string); ok {
+			if q, err := resource.ParseQuantity(s); err == nil && q.Cmp(v) == 0 {
+				m[string(k)] = s
+				continue
+			}
+		}
+		m[string(k)] = v.String()
+	}
+	return m
+}
+
+func writeResourceList(v interface{}) (api.ResourceList, error) {
+	l := make(api.ResourceList)
+	if m, _ := v.(map[string]interface{}); m != nil {
+		for k, x := range m {
+			s, _ := x.(string)
+			q, err := resource.ParseQuantity(s)
+			if err != nil {
+				return nil, fmt.Errorf("%s for %q", err, s)
+			}
+			l[api.ResourceName(k)] = *q
+		}
 	}
-	return parts[0], parts[1]
+	return l, nil
 }
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+)
+
+func TestSplit(t *testing.T) {
+	cases := []struct {
+		ID        string
+		Namespace string
+		Name      string
+		Err       bool
+	}{
+		{ID: "default/web", Namespace: "default", Name: "web"},
+		{ID: "web", Err: true},
+		{ID: "/web", Err: true},
+		{ID: "default/", Err: true},
+		{ID: "default/web/1", Err: true},
+	}
+
+	for i, tc := range cases {
+		namespace, name, err := split(tc.ID)
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: err: %v", i, err)
+		}
+		if namespace != tc.Namespace || name != tc.Name {
+			t.Fatalf("%d: bad: %q %q", i, namespace, name)
+		}
+	}
+}
+
+// TestDeleteGone deletes objects that were already removed, and objects with
+// an invalid id.
+func TestDeleteGone(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+
+	cases := []struct {
+		Resource   *schema.Resource
+		Attributes map[string]string
+		Split      bool
+	}{
+		{Resource: secretsResource(), Split: true},
+		{Resource: limitRangeResource(), Split: true},
+		{Resource: daemonSetResource(), Split: true},
+		{Resource: jobResource(), Split: true},
+		{
+			Resource: manifestResource(),
+			Attributes: map[string]string{
+				"manifest": `{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"web"}}`,
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		r := resourceData(t, tc.Resource, "default/web", tc.Attributes)
+		err := tc.Resource.Delete(r, s.meta())
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		if !tc.Split {
+			continue
+		}
+		r = resourceData(t, tc.Resource, "web", tc.Attributes)
+		err = tc.Resource.Delete(r, s.meta())
+		if err == nil {
+			t.Fatalf("%d: expected an error for an invalid id", i)
+		}
+	}
+}
+
+// TestRetryUpdate scales an RC while another writer keeps changing it. A few
+// conflicts are retried, a writer that always wins fails the update.
+func TestRetryUpdate(t *testing.T) {
+	cases := []struct {
+		Conflicts int
+		Err       bool
+	}{
+		{Conflicts: 0},
+		{Conflicts: 2},
+		{Conflicts: 10, Err: true},
+	}
+
+	const p = "/api/v1/namespaces/default/replicationcontrollers/web"
+	for i, tc := range cases {
+		s := newFakeServer(t)
+		item := &api.ReplicationController{}
+		item.Spec.Replicas = 1
+		s.put(p, item)
+		s.conflicts = tc.Conflicts
+
+		_, err := scaleController(extractClient(s.meta()).ReplicationControllers("default"), "web", 3)
+		item = &api.ReplicationController{}
+		s.get(p, item)
+		s.Close()
+
+		if tc.Err {
+			if !errors.IsConflict(err) {
+				t.Fatalf("%d: expected a conflict, got %v", i, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if item.Spec.Replicas != 3 {
+			t.Fatalf("%d: bad replicas: %d", i, item.Spec.Replicas)
+		}
+	}
+}
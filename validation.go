@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/validation"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+)
+
+// validateFunc builds the Kubernetes object described by r and validates it
+// with the api/validation package.
+type validateFunc func(r *schema.ResourceData) field.ErrorList
+
+// fieldMapping maps a prefix of a Kubernetes field path to the Terraform
+// attribute it is configured with.
+type fieldMapping struct {
+	field string
+	attr  string
+}
+
+// objectMetaMappings are shared by all resources that keep the object meta
+// at the top level.
+var objectMetaMappings = []fieldMapping{
+	{"metadata", ""},
+}
+
+// podTemplateFieldMappings are shared by the resources that have a pod
+// template.
+var podTemplateFieldMappings = []fieldMapping{
+	{"metadata", ""},
+	{"spec.template.metadata", "template.0"},
+	{"spec.template.spec", "template.0"},
+	{"spec.template", "template.0"},
+	{"spec", ""},
+}
+
+// validateExtensionsObjectMeta validates the object meta of an object of the
+// extensions group. Its validation is not vendored, the objects are validated
+// like the core objects they resemble.
+func validateExtensionsObjectMeta(meta *api.ObjectMeta) field.ErrorList {
+	return validation.ValidateObjectMeta(meta, true, validation.ValidateReplicationControllerName, field.NewPath("metadata"))
+}
+
+// validatePodTemplateObject validates an object of the extensions group with
+// a pod template, after write has built it from the configuration. The pod
+// template is validated like the one of a replication controller.
+func validatePodTemplateObject(
+	obj runtime.Object,
+	meta *api.ObjectMeta,
+	template *api.PodTemplateSpec,
+	write func() error,
+) field.ErrorList {
+	err := write()
+	if err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "template"), "", err.Error())}
+	}
+
+	err = applyDefaults(obj, "extensions/v1beta1")
+	if err != nil {
+		return field.ErrorList{field.InternalError(field.NewPath("spec"), err)}
+	}
+
+	allErrs := validateExtensionsObjectMeta(meta)
+	allErrs = append(allErrs, validation.ValidatePodTemplateSpec(template, field.NewPath("spec", "template"))...)
+	return allErrs
+}
+
+// provider runs the validators of the resources while diffing, so that
+// invalid objects are reported during plan instead of halfway through apply.
+type provider struct {
+	*schema.Provider
+
+	validators map[string]validateFunc
+	mappings   map[string][]fieldMapping
+}
+
+// Diff implementation of terraform.ResourceProvider interface.
+func (p *provider) Diff(
+	info *terraform.InstanceInfo,
+	s *terraform.InstanceState,
+	c *terraform.ResourceConfig) (*terraform.InstanceDiff, error) {
+
+	d, err := p.Provider.Diff(info, s, c)
+	if err != nil || d == nil || d.Destroy {
+		return d, err
+	}
+
+	if info.Type == "kubernetes_manifest" {
+		requireNewManifest(s, d)
+	}
+
+	// values that are only known during apply are validated then, when
+	// the diff is computed again
+	validate, ok := p.validators[info.Type]
+	if !ok || len(c.ComputedKeys) > 0 {
+		return d, nil
+	}
+
+	state := s.MergeDiff(d)
+	state.ID = ""
+	for k, v := range state.Attributes {
+		if v == config.UnknownVariableValue {
+			delete(state.Attributes, k)
+		}
+	}
+
+	// applying an empty diff to a state without an ID hands the planned
+	// attributes to Create, which is swapped for the validator
+	res := p.ResourcesMap[info.Type]
+	check := &schema.Resource{
+		Schema: res.Schema,
+	}
+
+	var errs field.ErrorList
+	check.Create = func(r *schema.ResourceData, _ interface{}) error {
+		errs = validate(r)
+		return nil
+	}
+	_, err = check.Apply(state, &terraform.InstanceDiff{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(errs) == 0 {
+		return d, nil
+	}
+
+	var result *multierror.Error
+	for _, e := range errs {
+		attr := attributePath(res.Schema, p.mappings[info.Type], e.Field)
+		if attr == "" {
+			result = multierror.Append(result, fmt.Errorf("%s", e.ErrorBody()))
+			continue
+		}
+		result = multierror.Append(result, fmt.Errorf("%s: %s", attr, e.ErrorBody()))
+	}
+	return nil, result
+}
+
+// applyDefaults round trips obj through the given API version so that the
+// defaults the API server would apply are set before it is validated.
+func applyDefaults(obj runtime.Object, version string) error {
+	ext, err := api.Scheme.ConvertToVersion(obj, version)
+	if err != nil {
+		return err
+	}
+	return api.Scheme.Convert(ext, obj)
+}
+
+// attributePath translates a Kubernetes field path (like
+// spec.template.spec.containers[0].ports[1].containerPort) to the path of the
+// matching Terraform attribute (template.0.container.0.port.1.container_port).
+func attributePath(s map[string]*schema.Schema, mappings []fieldMapping, path string) string {
+	var attr []string
+
+	for _, m := range mappings {
+		if path != m.field && !strings.HasPrefix(path, m.field+".") && !strings.HasPrefix(path, m.field+"[") {
+			continue
+		}
+		path = strings.TrimPrefix(strings.TrimPrefix(path, m.field), ".")
+		if m.attr == "" {
+			break
+		}
+		for _, k := range strings.Split(m.attr, ".") {
+			attr = append(attr, k)
+			if x, ok := s[k]; ok {
+				if r, ok := x.Elem.(*schema.Resource); ok {
+					s = r.Schema
+				}
+			}
+		}
+		break
+	}
+
+	for path != "" {
+		var name, index string
+		if i := strings.IndexAny(path, ".["); i < 0 {
+			name, path = path, ""
+		} else {
+			name, path = path[:i], path[i:]
+		}
+		if strings.HasPrefix(path, "[") {
+			j := strings.Index(path, "]")
+			if j < 0 {
+				j = len(path)
+				path += "]"
+			}
+			index, path = path[1:j], path[j+1:]
+		}
+		path = strings.TrimPrefix(path, ".")
+
+		key := snakeCase(name)
+		x, ok := s[key]
+		if !ok {
+			// lists are named in the singular in the schema
+			if y, found := s[strings.TrimSuffix(key, "s")]; found {
+				key, x, ok = strings.TrimSuffix(key, "s"), y, true
+			}
+		}
+		if key != "" {
+			attr = append(attr, key)
+		}
+		if !ok {
+			s = nil
+			if index != "" {
+				attr = append(attr, index)
+			}
+			continue
+		}
+
+		s = nil
+		switch x.Type {
+		case schema.TypeList:
+			r, isResource := x.Elem.(*schema.Resource)
+			if _, err := strconv.Atoi(index); isResource && err != nil {
+				// a block without an index (or indexed by a key) is the
+				// first and only element of the list
+				attr = append(attr, "0")
+			}
+			if index != "" {
+				attr = append(attr, index)
+			}
+			if isResource {
+				s = r.Schema
+			}
+		case schema.TypeMap:
+			if index != "" {
+				attr = append(attr, index)
+			}
+		case schema.TypeSet:
+			// set elements are addressed by hash, the set itself is
+			// as precise as it gets
+			return strings.Join(attr, ".")
+		}
+	}
+
+	return strings.Join(attr, ".")
+}
+
+// snakeCase turns a camel cased field name into the name used in the schema
+// (hostIP becomes host_ip).
+func snakeCase(s string) string {
+	var out []rune
+	var prev rune
+	for _, c := range s {
+		if unicode.IsUpper(c) && (unicode.IsLower(prev) || unicode.IsDigit(prev)) {
+			out = append(out, '_')
+		}
+		out = append(out, unicode.ToLower(c))
+		prev = c
+	}
+	return string(out)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestAttributePath(t *testing.T) {
+	s := map[string]*schema.Schema{
+		"name":     {Type: schema.TypeString},
+		"labels":   {Type: schema.TypeMap},
+		"replicas": {Type: schema.TypeInt},
+		"template": {
+			Type: schema.TypeList,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"labels":         {Type: schema.TypeMap},
+					"restart_policy": {Type: schema.TypeString},
+					"container": {
+						Type: schema.TypeList,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"image": {Type: schema.TypeString},
+								"port": {
+									Type: schema.TypeList,
+									Elem: &schema.Resource{
+										Schema: map[string]*schema.Schema{
+											"container_port": {Type: schema.TypeInt},
+										},
+									},
+								},
+								"security_context": {
+									Type: schema.TypeList,
+									Elem: &schema.Resource{
+										Schema: map[string]*schema.Schema{
+											"run_as_user": {Type: schema.TypeInt},
+										},
+									},
+								},
+							},
+						},
+					},
+					"volume": {
+						Type: schema.TypeSet,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"name": {Type: schema.TypeString},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		Field    string
+		Expected string
+	}{
+		{"metadata.name", "name"},
+		{"metadata.labels[app]", "labels.app"},
+		{"spec.replicas", "replicas"},
+		{"spec.template.metadata.labels[app]", "template.0.labels.app"},
+		{"spec.template.spec.restartPolicy", "template.0.restart_policy"},
+		{"spec.template.spec.containers[0].image", "template.0.container.0.image"},
+		{"spec.template.spec.containers[1].ports[2].containerPort", "template.0.container.1.port.2.container_port"},
+		{"spec.template.spec.containers[0].securityContext.runAsUser", "template.0.container.0.security_context.0.run_as_user"},
+		{"spec.template.spec.volumes[0].name", "template.0.volume"},
+		{"spec.template.spec.unknownField[2]", "template.0.unknown_field.2"},
+		{"spec.template", "template.0"},
+		{"spec.template.spec.containers[0", "template.0.container.0"},
+	}
+
+	for _, tc := range cases {
+		actual := attributePath(s, podTemplateFieldMappings, tc.Field)
+		if actual != tc.Expected {
+			t.Fatalf("%s: bad: %q", tc.Field, actual)
+		}
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	cases := []struct {
+		Input    string
+		Expected string
+	}{
+		{"name", "name"},
+		{"containerPort", "container_port"},
+		{"hostIP", "host_ip"},
+		{"podIP", "pod_ip"},
+		{"http2Port", "http2_port"},
+		{"TLS", "tls"},
+		{"", ""},
+	}
+
+	for _, tc := range cases {
+		actual := snakeCase(tc.Input)
+		if actual != tc.Expected {
+			t.Fatalf("%s: bad: %q", tc.Input, actual)
+		}
+	}
+}
@@ -0,0 +1,933 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+
+	"k8s.io/kubernetes/pkg/api"
+)
+
+var hostPathVolumeResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"path": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+	},
+}
+
+var gcePersistentDiskVolumeResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"pd_name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"fs_type": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"partition": {
+			Type:     schema.TypeInt,
+			Required: true,
+		},
+		"read_only": {
+			Type:     schema.TypeBool,
+			Required: true,
+		},
+	},
+}
+
+var awsElasticBlockStoreVolumeResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"volume_id": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"fs_type": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"partition": {
+			Type:     schema.TypeInt,
+			Required: true,
+		},
+		"read_only": {
+			Type:     schema.TypeBool,
+			Required: true,
+		},
+	},
+}
+
+var nfsVolumeResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"server": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"path": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"read_only": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	},
+}
+
+var persistentVolumeClaimVolumeResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"claim_name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"read_only": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	},
+}
+
+var iscsiVolumeResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"target_portal": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"iqn": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"lun": {
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+		"iscsi_interface": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "default",
+		},
+		"fs_type": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"read_only": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	},
+}
+
+var glusterfsVolumeResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"endpoints_name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"path": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"read_only": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	},
+}
+
+var rbdVolumeResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"ceph_monitors": {
+			Type:     schema.TypeList,
+			Required: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"rbd_image": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"fs_type": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"rbd_pool": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "rbd",
+		},
+		"rados_user": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "admin",
+		},
+		"keyring": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "/etc/ceph/keyring",
+		},
+		"secret_name": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"read_only": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	},
+}
+
+var flexVolumeResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"driver": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"fs_type": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"secret_name": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"read_only": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"options": {
+			Type:     schema.TypeMap,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+	},
+}
+
+var cinderVolumeResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"volume_id": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"fs_type": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"read_only": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	},
+}
+
+var cephFSVolumeResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"monitors": {
+			Type:     schema.TypeList,
+			Required: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"path": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"user": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"secret_file": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"secret_name": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"read_only": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	},
+}
+
+var flockerVolumeResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"dataset_name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+	},
+}
+
+var downwardAPIVolumeResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"item": {
+			Type:     schema.TypeList,
+			Required: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"path": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"field_ref": {
+						Type:     schema.TypeList,
+						Required: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"field_path": {
+									Type:     schema.TypeString,
+									Required: true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+var fcVolumeResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"target_wwns": {
+			Type:     schema.TypeList,
+			Required: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"lun": {
+			Type:     schema.TypeInt,
+			Required: true,
+		},
+		"fs_type": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"read_only": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	},
+}
+
+var azureFileVolumeResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"secret_name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"share_name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"read_only": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	},
+}
+
+var configMapVolumeResourceSpec = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"item": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"key": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"path": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+				},
+			},
+		},
+	},
+}
+
+func readHostPathVolumeSource(p ListParent, x *api.HostPathVolumeSource) {
+	if x == nil {
+		return
+	}
+	l := p.NewList("host_path")
+	l.Set("path", x.Path)
+	l.Apply()
+}
+
+func writeHostPathVolumeSource(v interface{}) *api.HostPathVolumeSource {
+	n, ok := extractSingleMap(v)
+	if !ok {
+		return nil
+	}
+	item := &api.HostPathVolumeSource{}
+	if x, ok := n["path"].(string); ok {
+		item.Path = x
+	}
+	return item
+}
+
+func readGCEPersistentDiskVolumeSource(p ListParent, x *api.GCEPersistentDiskVolumeSource) {
+	if x == nil {
+		return
+	}
+	l := p.NewList("gce_persistent_disk")
+	l.Set("pd_name", x.PDName)
+	l.Set("fs_type", x.FSType)
+	l.Set("partition", x.Partition)
+	l.Set("read_only", x.ReadOnly)
+	l.Apply()
+}
+
+func writeGCEPersistentDiskVolumeSource(v interface{}) *api.GCEPersistentDiskVolumeSource {
+	n, ok := extractSingleMap(v)
+	if !ok {
+		return nil
+	}
+	item := &api.GCEPersistentDiskVolumeSource{}
+	if x, ok := n["pd_name"].(string); ok {
+		item.PDName = x
+	}
+	if x, ok := n["fs_type"].(string); ok {
+		item.FSType = x
+	}
+	if x, ok := n["partition"].(int); ok {
+		item.Partition = x
+	}
+	if x, ok := n["read_only"].(bool); ok {
+		item.ReadOnly = x
+	}
+	return item
+}
+
+func readAWSElasticBlockStoreVolumeSource(p ListParent, x *api.AWSElasticBlockStoreVolumeSource) {
+	if x == nil {
+		return
+	}
+	l := p.NewList("aws_elastic_block_store")
+	l.Set("volume_id", x.VolumeID)
+	l.Set("fs_type", x.FSType)
+	l.Set("partition", x.Partition)
+	l.Set("read_only", x.ReadOnly)
+	l.Apply()
+}
+
+func writeAWSElasticBlockStoreVolumeSource(v interface{}) *api.AWSElasticBlockStoreVolumeSource {
+	n, ok := extractSingleMap(v)
+	if !ok {
+		return nil
+	}
+	item := &api.AWSElasticBlockStoreVolumeSource{}
+	if x, ok := n["volume_id"].(string); ok {
+		item.VolumeID = x
+	}
+	if x, ok := n["fs_type"].(string); ok {
+		item.FSType = x
+	}
+	if x, ok := n["partition"].(int); ok {
+		item.Partition = x
+	}
+	if x, ok := n["read_only"].(bool); ok {
+		item.ReadOnly = x
+	}
+	return item
+}
+
+func readNFSVolumeSource(p ListParent, x *api.NFSVolumeSource) {
+	if x == nil {
+		return
+	}
+	l := p.NewList("nfs")
+	l.Set("server", x.Server)
+	l.Set("path", x.Path)
+	l.Set("read_only", x.ReadOnly)
+	l.Apply()
+}
+
+func writeNFSVolumeSource(v interface{}) *api.NFSVolumeSource {
+	n, ok := extractSingleMap(v)
+	if !ok {
+		return nil
+	}
+	item := &api.NFSVolumeSource{}
+	if x, ok := n["server"].(string); ok {
+		item.Server = x
+	}
+	if x, ok := n["path"].(string); ok {
+		item.Path = x
+	}
+	if x, ok := n["read_only"].(bool); ok {
+		item.ReadOnly = x
+	}
+	return item
+}
+
+func readPersistentVolumeClaimVolumeSource(p ListParent, x *api.PersistentVolumeClaimVolumeSource) {
+	if x == nil {
+		return
+	}
+	l := p.NewList("persistent_volume_claim")
+	l.Set("claim_name", x.ClaimName)
+	l.Set("read_only", x.ReadOnly)
+	l.Apply()
+}
+
+func writePersistentVolumeClaimVolumeSource(v interface{}) *api.PersistentVolumeClaimVolumeSource {
+	n, ok := extractSingleMap(v)
+	if !ok {
+		return nil
+	}
+	item := &api.PersistentVolumeClaimVolumeSource{}
+	if x, ok := n["claim_name"].(string); ok {
+		item.ClaimName = x
+	}
+	if x, ok := n["read_only"].(bool); ok {
+		item.ReadOnly = x
+	}
+	return item
+}
+
+func readISCSIVolumeSource(p ListParent, x *api.ISCSIVolumeSource) {
+	if x == nil {
+		return
+	}
+	l := p.NewList("iscsi")
+	l.Set("target_portal", x.TargetPortal)
+	l.Set("iqn", x.IQN)
+	l.Set("lun", x.Lun)
+	l.Set("iscsi_interface", x.ISCSIInterface)
+	l.Set("fs_type", x.FSType)
+	l.Set("read_only", x.ReadOnly)
+	l.Apply()
+}
+
+func writeISCSIVolumeSource(v interface{}) *api.ISCSIVolumeSource {
+	n, ok := extractSingleMap(v)
+	if !ok {
+		return nil
+	}
+	item := &api.ISCSIVolumeSource{}
+	if x, ok := n["target_portal"].(string); ok {
+		item.TargetPortal = x
+	}
+	if x, ok := n["iqn"].(string); ok {
+		item.IQN = x
+	}
+	if x, ok := n["lun"].(int); ok {
+		item.Lun = x
+	}
+	if x, ok := n["iscsi_interface"].(string); ok {
+		item.ISCSIInterface = x
+	}
+	if x, ok := n["fs_type"].(string); ok {
+		item.FSType = x
+	}
+	if x, ok := n["read_only"].(bool); ok {
+		item.ReadOnly = x
+	}
+	return item
+}
+
+func readGlusterfsVolumeSource(p ListParent, x *api.GlusterfsVolumeSource) {
+	if x == nil {
+		return
+	}
+	l := p.NewList("glusterfs")
+	l.Set("endpoints_name", x.EndpointsName)
+	l.Set("path", x.Path)
+	l.Set("read_only", x.ReadOnly)
+	l.Apply()
+}
+
+func writeGlusterfsVolumeSource(v interface{}) *api.GlusterfsVolumeSource {
+	n, ok := extractSingleMap(v)
+	if !ok {
+		return nil
+	}
+	item := &api.GlusterfsVolumeSource{}
+	if x, ok := n["endpoints_name"].(string); ok {
+		item.EndpointsName = x
+	}
+	if x, ok := n["path"].(string); ok {
+		item.Path = x
+	}
+	if x, ok := n["read_only"].(bool); ok {
+		item.ReadOnly = x
+	}
+	return item
+}
+
+func readRBDVolumeSource(p ListParent, x *api.RBDVolumeSource) {
+	if x == nil {
+		return
+	}
+	l := p.NewList("rbd")
+	l.Set("ceph_monitors", x.CephMonitors)
+	l.Set("rbd_image", x.RBDImage)
+	l.Set("fs_type", x.FSType)
+	l.Set("rbd_pool", x.RBDPool)
+	l.Set("rados_user", x.RadosUser)
+	l.Set("keyring", x.Keyring)
+	if x.SecretRef != nil {
+		l.Set("secret_name", x.SecretRef.Name)
+	}
+	l.Set("read_only", x.ReadOnly)
+	l.Apply()
+}
+
+func writeRBDVolumeSource(v interface{}) *api.RBDVolumeSource {
+	n, ok := extractSingleMap(v)
+	if !ok {
+		return nil
+	}
+	item := &api.RBDVolumeSource{}
+	if l, ok := n["ceph_monitors"].([]interface{}); ok {
+		for _, x := range l {
+			item.CephMonitors = append(item.CephMonitors, x.(string))
+		}
+	}
+	if x, ok := n["rbd_image"].(string); ok {
+		item.RBDImage = x
+	}
+	if x, ok := n["fs_type"].(string); ok {
+		item.FSType = x
+	}
+	if x, ok := n["rbd_pool"].(string); ok {
+		item.RBDPool = x
+	}
+	if x, ok := n["rados_user"].(string); ok {
+		item.RadosUser = x
+	}
+	if x, ok := n["keyring"].(string); ok {
+		item.Keyring = x
+	}
+	if x, ok := n["secret_name"].(string); ok && x != "" {
+		item.SecretRef = &api.LocalObjectReference{Name: x}
+	}
+	if x, ok := n["read_only"].(bool); ok {
+		item.ReadOnly = x
+	}
+	return item
+}
+
+func readFlexVolumeSource(p ListParent, x *api.FlexVolumeSource) {
+	if x == nil {
+		return
+	}
+	l := p.NewList("flex_volume")
+	l.Set("driver", x.Driver)
+	l.Set("fs_type", x.FSType)
+	if x.SecretRef != nil {
+		l.Set("secret_name", x.SecretRef.Name)
+	}
+	l.Set("read_only", x.ReadOnly)
+	if x.Options != nil {
+		var options = map[string]interface{}{}
+		for k, v := range x.Options {
+			options[k] = v
+		}
+		l.Set("options", options)
+	}
+	l.Apply()
+}
+
+func writeFlexVolumeSource(v interface{}) *api.FlexVolumeSource {
+	n, ok := extractSingleMap(v)
+	if !ok {
+		return nil
+	}
+	item := &api.FlexVolumeSource{}
+	if x, ok := n["driver"].(string); ok {
+		item.Driver = x
+	}
+	if x, ok := n["fs_type"].(string); ok {
+		item.FSType = x
+	}
+	if x, ok := n["secret_name"].(string); ok && x != "" {
+		item.SecretRef = &api.LocalObjectReference{Name: x}
+	}
+	if x, ok := n["read_only"].(bool); ok {
+		item.ReadOnly = x
+	}
+	if x, ok := n["options"].(map[string]interface{}); ok && len(x) > 0 {
+		item.Options = map[string]string{}
+		for k, v := range x {
+			item.Options[k] = v.(string)
+		}
+	}
+	return item
+}
+
+func readCinderVolumeSource(p ListParent, x *api.CinderVolumeSource) {
+	if x == nil {
+		return
+	}
+	l := p.NewList("cinder")
+	l.Set("volume_id", x.VolumeID)
+	l.Set("fs_type", x.FSType)
+	l.Set("read_only", x.ReadOnly)
+	l.Apply()
+}
+
+func writeCinderVolumeSource(v interface{}) *api.CinderVolumeSource {
+	n, ok := extractSingleMap(v)
+	if !ok {
+		return nil
+	}
+	item := &api.CinderVolumeSource{}
+	if x, ok := n["volume_id"].(string); ok {
+		item.VolumeID = x
+	}
+	if x, ok := n["fs_type"].(string); ok {
+		item.FSType = x
+	}
+	if x, ok := n["read_only"].(bool); ok {
+		item.ReadOnly = x
+	}
+	return item
+}
+
+func readCephFSVolumeSource(p ListParent, x *api.CephFSVolumeSource) {
+	if x == nil {
+		return
+	}
+	l := p.NewList("ceph_fs")
+	l.Set("monitors", x.Monitors)
+	l.Set("path", x.Path)
+	l.Set("user", x.User)
+	l.Set("secret_file", x.SecretFile)
+	if x.SecretRef != nil {
+		l.Set("secret_name", x.SecretRef.Name)
+	}
+	l.Set("read_only", x.ReadOnly)
+	l.Apply()
+}
+
+func writeCephFSVolumeSource(v interface{}) *api.CephFSVolumeSource {
+	n, ok := extractSingleMap(v)
+	if !ok {
+		return nil
+	}
+	item := &api.CephFSVolumeSource{}
+	if l, ok := n["monitors"].([]interface{}); ok {
+		for _, x := range l {
+			item.Monitors = append(item.Monitors, x.(string))
+		}
+	}
+	if x, ok := n["path"].(string); ok {
+		item.Path = x
+	}
+	if x, ok := n["user"].(string); ok {
+		item.User = x
+	}
+	if x, ok := n["secret_file"].(string); ok {
+		item.SecretFile = x
+	}
+	if x, ok := n["secret_name"].(string); ok && x != "" {
+		item.SecretRef = &api.LocalObjectReference{Name: x}
+	}
+	if x, ok := n["read_only"].(bool); ok {
+		item.ReadOnly = x
+	}
+	return item
+}
+
+func readFlockerVolumeSource(p ListParent, x *api.FlockerVolumeSource) {
+	if x == nil {
+		return
+	}
+	l := p.NewList("flocker")
+	l.Set("dataset_name", x.DatasetName)
+	l.Apply()
+}
+
+func writeFlockerVolumeSource(v interface{}) *api.FlockerVolumeSource {
+	n, ok := extractSingleMap(v)
+	if !ok {
+		return nil
+	}
+	item := &api.FlockerVolumeSource{}
+	if x, ok := n["dataset_name"].(string); ok {
+		item.DatasetName = x
+	}
+	return item
+}
+
+func readDownwardAPIVolumeSource(p ListParent, x *api.DownwardAPIVolumeSource) {
+	if x == nil {
+		return
+	}
+	l := p.NewList("downward_api")
+	items := l.NewList("item")
+	for _, y := range x.Items {
+		items.Next()
+		items.Set("path", y.Path)
+		fieldRef := items.NewList("field_ref")
+		fieldRef.Set("field_path", y.FieldRef.FieldPath)
+		fieldRef.Apply()
+	}
+	items.Apply()
+	l.Apply()
+}
+
+func writeDownwardAPIVolumeSource(v interface{}) *api.DownwardAPIVolumeSource {
+	n, ok := extractSingleMap(v)
+	if !ok {
+		return nil
+	}
+	item := &api.DownwardAPIVolumeSource{}
+	if l, ok := n["item"].([]interface{}); ok {
+		for _, x := range l {
+			m, ok := extractSingleMap(x)
+			if !ok {
+				continue
+			}
+			f := api.DownwardAPIVolumeFile{}
+			if x, ok := m["path"].(string); ok {
+				f.Path = x
+			}
+			if o, ok := extractSingleMap(m["field_ref"]); ok {
+				if x, ok := o["field_path"].(string); ok {
+					f.FieldRef.FieldPath = x
+				}
+			}
+			item.Items = append(item.Items, f)
+		}
+	}
+	return item
+}
+
+func readFCVolumeSource(p ListParent, x *api.FCVolumeSource) {
+	if x == nil {
+		return
+	}
+	l := p.NewList("fc")
+	l.Set("target_wwns", x.TargetWWNs)
+	if x.Lun != nil {
+		l.Set("lun", *x.Lun)
+	}
+	l.Set("fs_type", x.FSType)
+	l.Set("read_only", x.ReadOnly)
+	l.Apply()
+}
+
+func writeFCVolumeSource(v interface{}) *api.FCVolumeSource {
+	n, ok := extractSingleMap(v)
+	if !ok {
+		return nil
+	}
+	item := &api.FCVolumeSource{}
+	if l, ok := n["target_wwns"].([]interface{}); ok {
+		for _, x := range l {
+			item.TargetWWNs = append(item.TargetWWNs, x.(string))
+		}
+	}
+	if x, ok := n["lun"].(int); ok {
+		item.Lun = &x
+	}
+	if x, ok := n["fs_type"].(string); ok {
+		item.FSType = x
+	}
+	if x, ok := n["read_only"].(bool); ok {
+		item.ReadOnly = x
+	}
+	return item
+}
+
+func readAzureFileVolumeSource(p ListParent, x *api.AzureFileVolumeSource) {
+	if x == nil {
+		return
+	}
+	l := p.NewList("azure_file")
+	l.Set("secret_name", x.SecretName)
+	l.Set("share_name", x.ShareName)
+	l.Set("read_only", x.ReadOnly)
+	l.Apply()
+}
+
+func writeAzureFileVolumeSource(v interface{}) *api.AzureFileVolumeSource {
+	n, ok := extractSingleMap(v)
+	if !ok {
+		return nil
+	}
+	item := &api.AzureFileVolumeSource{}
+	if x, ok := n["secret_name"].(string); ok {
+		item.SecretName = x
+	}
+	if x, ok := n["share_name"].(string); ok {
+		item.ShareName = x
+	}
+	if x, ok := n["read_only"].(bool); ok {
+		item.ReadOnly = x
+	}
+	return item
+}
+
+func readConfigMapVolumeSource(p ListParent, x *api.ConfigMapVolumeSource) {
+	if x == nil {
+		return
+	}
+	l := p.NewList("config_map")
+	l.Set("name", x.Name)
+	if x.Items != nil {
+		items := l.NewList("item")
+		for _, y := range x.Items {
+			items.Next()
+			items.Set("key", y.Key)
+			items.Set("path", y.Path)
+		}
+		items.Apply()
+	}
+	l.Apply()
+}
+
+func writeConfigMapVolumeSource(v interface{}) *api.ConfigMapVolumeSource {
+	n, ok := extractSingleMap(v)
+	if !ok {
+		return nil
+	}
+	item := &api.ConfigMapVolumeSource{}
+	if x, ok := n["name"].(string); ok {
+		item.Name = x
+	}
+	if l, ok := n["item"].([]interface{}); ok {
+		for _, x := range l {
+			m, ok := extractSingleMap(x)
+			if !ok {
+				continue
+			}
+			k := api.KeyToPath{}
+			if x, ok := m["key"].(string); ok {
+				k.Key = x
+			}
+			if x, ok := m["path"].(string); ok {
+				k.Path = x
+			}
+			item.Items = append(item.Items, k)
+		}
+	}
+	return item
+}
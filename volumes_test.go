@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+)
+
+// TestPodVolumesRoundTrip reads the volumes of an RC into the state and
+// writes them back. Once the server defaults are applied they are the
+// volumes the server stored.
+func TestPodVolumesRoundTrip(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+
+	lun := 0
+	volumes := []api.Volume{
+		{Name: "host-path", VolumeSource: api.VolumeSource{
+			HostPath: &api.HostPathVolumeSource{Path: "/data"},
+		}},
+		{Name: "empty-dir", VolumeSource: api.VolumeSource{
+			EmptyDir: &api.EmptyDirVolumeSource{Medium: api.StorageMediumMemory},
+		}},
+		{Name: "gce", VolumeSource: api.VolumeSource{
+			GCEPersistentDisk: &api.GCEPersistentDiskVolumeSource{PDName: "disk", FSType: "ext4", Partition: 1, ReadOnly: true},
+		}},
+		{Name: "aws", VolumeSource: api.VolumeSource{
+			AWSElasticBlockStore: &api.AWSElasticBlockStoreVolumeSource{VolumeID: "vol-1", FSType: "ext4"},
+		}},
+		{Name: "nfs", VolumeSource: api.VolumeSource{
+			NFS: &api.NFSVolumeSource{Server: "nfs.local", Path: "/exports", ReadOnly: true},
+		}},
+		{Name: "claim", VolumeSource: api.VolumeSource{
+			PersistentVolumeClaim: &api.PersistentVolumeClaimVolumeSource{ClaimName: "data"},
+		}},
+		{Name: "iscsi", VolumeSource: api.VolumeSource{
+			ISCSI: &api.ISCSIVolumeSource{TargetPortal: "10.0.0.1:3260", IQN: "iqn.2001-04.com.example:storage", Lun: 1, FSType: "ext4"},
+		}},
+		{Name: "glusterfs", VolumeSource: api.VolumeSource{
+			Glusterfs: &api.GlusterfsVolumeSource{EndpointsName: "gluster", Path: "data"},
+		}},
+		{Name: "rbd", VolumeSource: api.VolumeSource{
+			RBD: &api.RBDVolumeSource{
+				CephMonitors: []string{"10.0.0.1:6789"},
+				RBDImage:     "data",
+				FSType:       "ext4",
+				SecretRef:    &api.LocalObjectReference{Name: "ceph"},
+			},
+		}},
+		{Name: "flex", VolumeSource: api.VolumeSource{
+			FlexVolume: &api.FlexVolumeSource{Driver: "example/lvm", Options: map[string]string{"size": "1G"}},
+		}},
+		{Name: "cinder", VolumeSource: api.VolumeSource{
+			Cinder: &api.CinderVolumeSource{VolumeID: "1234", FSType: "ext4"},
+		}},
+		{Name: "cephfs", VolumeSource: api.VolumeSource{
+			CephFS: &api.CephFSVolumeSource{Monitors: []string{"10.0.0.1:6789"}, User: "admin", SecretFile: "/etc/ceph/secret"},
+		}},
+		{Name: "flocker", VolumeSource: api.VolumeSource{
+			Flocker: &api.FlockerVolumeSource{DatasetName: "data"},
+		}},
+		{Name: "downward-api", VolumeSource: api.VolumeSource{
+			DownwardAPI: &api.DownwardAPIVolumeSource{Items: []api.DownwardAPIVolumeFile{
+				{Path: "labels", FieldRef: api.ObjectFieldSelector{APIVersion: "v1", FieldPath: "metadata.labels"}},
+			}},
+		}},
+		{Name: "fc", VolumeSource: api.VolumeSource{
+			FC: &api.FCVolumeSource{TargetWWNs: []string{"500a0981891b8dc5"}, Lun: &lun, FSType: "ext4"},
+		}},
+		{Name: "azure-file", VolumeSource: api.VolumeSource{
+			AzureFile: &api.AzureFileVolumeSource{SecretName: "azure", ShareName: "data"},
+		}},
+		{Name: "config-map", VolumeSource: api.VolumeSource{
+			ConfigMap: &api.ConfigMapVolumeSource{
+				LocalObjectReference: api.LocalObjectReference{Name: "web"},
+				Items:                []api.KeyToPath{{Key: "config", Path: "config.yaml"}},
+			},
+		}},
+		{Name: "secret", VolumeSource: api.VolumeSource{
+			Secret: &api.SecretVolumeSource{SecretName: "web"},
+		}},
+	}
+
+	item := &api.ReplicationController{}
+	item.Spec.Replicas = 1
+	item.Spec.Selector = map[string]string{"app": "web", "deployment": "1"}
+	item.Spec.Template = &api.PodTemplateSpec{}
+	item.Spec.Template.Labels = item.Spec.Selector
+	item.Spec.Template.Spec.Volumes = volumes
+	item.Spec.Template.Spec.Containers = []api.Container{{Name: "web", Image: "web:1"}}
+	// the server stores the object with its defaults
+	err := applyDefaults(item, "v1")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	const p = "/api/v1/namespaces/default/replicationcontrollers/web"
+	s.put(p, item)
+
+	stored := &api.ReplicationController{}
+	s.get(p, stored)
+
+	res := replicationControllerResource()
+	r := resourceData(t, res, "default/web", map[string]string{"namespace": "default", "name": "web"})
+	err = res.Read(r, s.meta())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	written := &api.ReplicationController{}
+	written.Spec.Template = &api.PodTemplateSpec{}
+	err = writePodTemplateSpec(r, written.Spec.Template)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	err = applyDefaults(written, "v1")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := written.Spec.Template.Spec.Volumes
+	expected := stored.Spec.Template.Spec.Volumes
+	if len(actual) != len(expected) {
+		t.Fatalf("bad: %d volumes", len(actual))
+	}
+	for i := range expected {
+		if !api.Semantic.DeepEqual(actual[i], expected[i]) {
+			a, _ := json.Marshal(actual[i])
+			e, _ := json.Marshal(expected[i])
+			t.Fatalf("bad %s:\n%s\n%s", expected[i].Name, a, e)
+		}
+	}
+}